@@ -1,33 +1,161 @@
 package api
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"slices"
 	"strings"
 
+	didlib "github.com/nuts-foundation/go-did/did"
+	"github.com/nuts-foundation/nuts-admin/capture"
+	"github.com/nuts-foundation/nuts-admin/credentialprofile"
 	"github.com/nuts-foundation/nuts-admin/discovery"
+	"github.com/nuts-foundation/nuts-admin/expiry"
 	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/inspector"
 	"github.com/nuts-foundation/nuts-admin/issuer"
-	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/nuts-foundation/nuts-admin/job"
+	"github.com/nuts-foundation/nuts-admin/lint"
+	"github.com/nuts-foundation/nuts-admin/oidc"
+	"github.com/nuts-foundation/nuts-admin/openid4vci"
+	"github.com/nuts-foundation/nuts-admin/pe"
+	"github.com/nuts-foundation/nuts-admin/policy"
+	"github.com/nuts-foundation/nuts-admin/provisioning"
+	"github.com/nuts-foundation/nuts-admin/registration"
+	"github.com/nuts-foundation/nuts-admin/reload"
 
 	"github.com/labstack/echo/v4"
 )
 
 var _ ServerInterface = (*Wrapper)(nil)
 
+// maxWalletImportSize is the maximum size of credentials uploaded or imported into a wallet.
+const maxWalletImportSize = 10 * 1024 * 1024
+
 type Wrapper struct {
-	Identity           identity.Service
-	IssuerService      issuer.Service
-	Discovery          discovery.Service
-	CredentialProfiles []CredentialProfile
+	Identity      identity.Service
+	IssuerService issuer.Service
+	Discovery     discovery.Service
+	Provisioning  provisioning.Service
+	Jobs          *job.Runner
+	Expiry        *expiry.Monitor
+	Registrations *registration.Monitor
+	Inspector     inspector.Service
+	Policy        policy.Service
+	// ProxyCaptures contains the captured proxy requests, it's nil if capturing is disabled.
+	ProxyCaptures      *capture.Recorder
+	CredentialProfiles credentialprofile.Service
+	CredentialRequests openid4vci.Tracker
+	ConfigReload       *reload.Watcher
 }
 
 func (w Wrapper) GetConfig(ctx echo.Context) error {
 	config := Config{
-		CredentialProfiles: w.CredentialProfiles,
+		CredentialProfiles: w.CredentialProfiles.List(),
+		CredentialRequests: w.CredentialRequests.Enabled(),
+		ServiceProfiles:    w.Identity.ServiceProfiles,
+	}
+	if config.ServiceProfiles == nil {
+		config.ServiceProfiles = make([]ServiceProfile, 0)
 	}
 	return ctx.JSON(http.StatusOK, config)
 }
 
+func (w Wrapper) GetConfigReloadStatus(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, w.ConfigReload.Report())
+}
+
+func (w Wrapper) ListCredentialProfiles(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, w.CredentialProfiles.List())
+}
+
+func (w Wrapper) CreateCredentialProfile(ctx echo.Context) error {
+	request := CreateCredentialProfileJSONRequestBody{}
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	result, err := w.CredentialProfiles.Create(request)
+	if err != nil {
+		return credentialProfileError(err)
+	}
+	return ctx.JSON(http.StatusCreated, result)
+}
+
+func (w Wrapper) DeleteCredentialProfile(ctx echo.Context, id string) error {
+	if err := w.CredentialProfiles.Delete(id); err != nil {
+		return credentialProfileError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (w Wrapper) GetCredentialProfile(ctx echo.Context, id string) error {
+	result, err := w.CredentialProfiles.Get(id)
+	if err != nil {
+		return credentialProfileError(err)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) UpdateCredentialProfile(ctx echo.Context, id string) error {
+	request := UpdateCredentialProfileJSONRequestBody{}
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	result, err := w.CredentialProfiles.Update(id, request)
+	if err != nil {
+		return credentialProfileError(err)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// credentialProfileError maps credential profile errors to the appropriate HTTP status.
+func credentialProfileError(err error) error {
+	switch {
+	case errors.Is(err, credentialprofile.ErrInvalidProfile):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, credentialprofile.ErrSubjectNotAllowed), errors.Is(err, credentialprofile.ErrReadOnly):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, credentialprofile.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, credentialprofile.ErrExists):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return err
+	}
+}
+
+func (w Wrapper) GetExpiringCredentials(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, w.Expiry.Report())
+}
+
+func (w Wrapper) ScanExpiringCredentials(ctx echo.Context) error {
+	// Scan errors and failed notifications are part of the report
+	_ = w.Expiry.Scan(ctx.Request().Context())
+	return ctx.JSON(http.StatusOK, w.Expiry.Report())
+}
+
+func (w Wrapper) GetDiscoveryHealth(ctx echo.Context, params GetDiscoveryHealthParams) error {
+	if params.Refresh != nil && *params.Refresh {
+		// Check errors are part of the report
+		_ = w.Registrations.Check(ctx.Request().Context())
+	}
+	return ctx.JSON(http.StatusOK, w.Registrations.Report())
+}
+
+func (w Wrapper) GetProxyCaptures(ctx echo.Context) error {
+	if w.ProxyCaptures == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "capturing proxied requests is disabled")
+	}
+	if role := w.ProxyCaptures.Config.Role; role != "" && !slices.Contains(oidc.Roles(ctx), role) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("viewing proxy captures requires role %s", role))
+	}
+	return ctx.JSON(http.StatusOK, w.ProxyCaptures.Records())
+}
+
 func (w Wrapper) GetIdentities(ctx echo.Context) error {
 	identities, err := w.Identity.List(ctx.Request().Context())
 	if err != nil {
@@ -56,20 +184,434 @@ func (w Wrapper) GetIdentity(ctx echo.Context, did string) error {
 	return ctx.JSON(http.StatusOK, details)
 }
 
-func (w Wrapper) GetIssuedCredentials(ctx echo.Context, params GetIssuedCredentialsParams) error {
-	identities, err := w.Identity.List(ctx.Request().Context())
+func (w Wrapper) DeactivateIdentity(ctx echo.Context, did string, params DeactivateIdentityParams) error {
+	if params.Confirm != did {
+		return echo.NewHTTPError(http.StatusBadRequest, "deactivation not confirmed: confirm must be equal to the subject")
+	}
+	if err := w.Identity.Deactivate(ctx.Request().Context(), did); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (w Wrapper) AddVerificationMethod(ctx echo.Context, did string) error {
+	result, err := w.Identity.AddVerificationMethod(ctx.Request().Context(), did)
 	if err != nil {
 		return err
 	}
-	result := make([]model.CredentialWithStatus, 0)
-	for _, currID := range identities {
-		for _, issuerDID := range currID.DIDs {
-			credentials, err := w.IssuerService.GetIssuedCredentials(ctx.Request().Context(), issuerDID, strings.Split(params.CredentialTypes, ","))
-			if err != nil {
-				return err
-			}
-			result = append(result, credentials...)
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) GetIdentityServices(ctx echo.Context, did string) error {
+	result, err := w.Identity.ListServices(ctx.Request().Context(), did)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) CreateIdentityService(ctx echo.Context, did string) error {
+	serviceRequest := CreateIdentityServiceJSONRequestBody{}
+	if err := ctx.Bind(&serviceRequest); err != nil {
+		return err
+	}
+	result, err := w.Identity.CreateService(ctx.Request().Context(), did, didlib.Service{
+		Type:            serviceRequest.Type,
+		ServiceEndpoint: serviceRequest.ServiceEndpoint,
+	})
+	if err != nil {
+		return serviceError(err)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) UpdateIdentityService(ctx echo.Context, did string, serviceID string) error {
+	serviceRequest := UpdateIdentityServiceJSONRequestBody{}
+	if err := ctx.Bind(&serviceRequest); err != nil {
+		return err
+	}
+	result, err := w.Identity.UpdateService(ctx.Request().Context(), did, serviceID, didlib.Service{
+		Type:            serviceRequest.Type,
+		ServiceEndpoint: serviceRequest.ServiceEndpoint,
+	})
+	if err != nil {
+		return serviceError(err)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) DeleteIdentityService(ctx echo.Context, did string, serviceID string, params DeleteIdentityServiceParams) error {
+	if params.Confirm != serviceID {
+		return echo.NewHTTPError(http.StatusBadRequest, "deletion not confirmed: confirm must be equal to the service ID")
+	}
+	if err := w.Identity.DeleteService(ctx.Request().Context(), did, serviceID); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (w Wrapper) ActivateDiscoveryService(ctx echo.Context, did string, serviceID string) error {
+	// The request body is optional
+	request := ActivateDiscoveryServiceJSONRequestBody{}
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	var registrationParameters map[string]interface{}
+	if request.RegistrationParameters != nil {
+		registrationParameters = *request.RegistrationParameters
+	}
+	if err := w.Discovery.Activate(ctx.Request().Context(), serviceID, did, registrationParameters); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (w Wrapper) DeactivateDiscoveryService(ctx echo.Context, did string, serviceID string) error {
+	if err := w.Discovery.Deactivate(ctx.Request().Context(), serviceID, did); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (w Wrapper) RequestCredential(ctx echo.Context, did string) error {
+	request := RequestCredentialJSONRequestBody{}
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	// Credentials are only requested through profiles, so the subject and wallet DID are always checked
+	if request.ProfileID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "profile_id is required")
+	}
+	profile, err := w.CredentialProfiles.Get(request.ProfileID)
+	if err != nil {
+		return credentialProfileError(err)
+	}
+	if request, err = credentialprofile.Apply(*profile, did, request); err != nil {
+		if errors.Is(err, identity.ErrInvalidCredentialRequest) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
+		return credentialProfileError(err)
+	}
+	result, err := w.CredentialRequests.Start(ctx.Request().Context(), did, request, profile.Type)
+	if errors.Is(err, identity.ErrInvalidCredentialRequest) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if errors.Is(err, openid4vci.ErrNoBaseURL) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	} else if err != nil {
+		return err
 	}
 	return ctx.JSON(http.StatusOK, result)
 }
+
+func (w Wrapper) ListCredentialRequests(ctx echo.Context, did string) error {
+	return ctx.JSON(http.StatusOK, w.CredentialRequests.List(did))
+}
+
+// CompleteCredentialRequest is the redirect URI of credential requests: the user returns here from the issuer.
+// It records the outcome and redirects the user to the identity in the web UI, which shows it.
+func (w Wrapper) CompleteCredentialRequest(ctx echo.Context, params CompleteCredentialRequestParams) error {
+	var errorCode, errorDescription string
+	if params.Error != nil {
+		errorCode = *params.Error
+	}
+	if params.ErrorDescription != nil {
+		errorDescription = *params.ErrorDescription
+	}
+	result, err := w.CredentialRequests.Complete(ctx.Request().Context(), params.State, errorCode, errorDescription)
+	if errors.Is(err, openid4vci.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.Redirect(http.StatusFound, w.CredentialRequests.ResultURL(*result))
+}
+
+func (w Wrapper) UploadCredential(ctx echo.Context, did string) error {
+	data, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxWalletImportSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxWalletImportSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "credential must not exceed 10 MiB")
+	}
+	result, err := w.Identity.UploadCredential(ctx.Request().Context(), did, string(data))
+	if errors.Is(err, identity.ErrInvalidCredential) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) ExportWalletCredentials(ctx echo.Context, did string) error {
+	buf := new(bytes.Buffer)
+	if err := w.Identity.ExportWallet(ctx.Request().Context(), did, buf); err != nil {
+		return err
+	}
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s-wallet.zip"`, did))
+	return ctx.Blob(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+func (w Wrapper) ImportWalletCredentials(ctx echo.Context, did string) error {
+	data, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxWalletImportSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxWalletImportSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "credentials must not exceed 10 MiB")
+	}
+	result, err := w.Identity.ImportWallet(ctx.Request().Context(), did, data)
+	if errors.Is(err, identity.ErrInvalidBundle) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) RemoveWalletCredential(ctx echo.Context, did string, credentialID string) error {
+	if err := w.Identity.RemoveCredential(ctx.Request().Context(), did, credentialID); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (w Wrapper) ImportIdentities(ctx echo.Context, params ImportIdentitiesParams) error {
+	var manifest *provisioning.Manifest
+	var err error
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case "text/csv":
+		manifest, err = provisioning.ParseCSV(ctx.Request().Body)
+	case echo.MIMEApplicationJSON:
+		manifest, err = provisioning.ParseJSON(ctx.Request().Body)
+	default:
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "manifest must be JSON or CSV")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	result, err := w.Provisioning.Import(ctx.Request().Context(), *manifest, params.Rerun != nil && *params.Rerun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return ctx.JSON(http.StatusAccepted, result)
+}
+
+func (w Wrapper) IssueCredential(ctx echo.Context) error {
+	request := IssueCredentialJSONRequestBody{}
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	result, err := w.IssuerService.IssueCredential(ctx.Request().Context(), request)
+	if errors.Is(err, issuer.ErrInvalidIssueRequest) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) RevokeCredential(ctx echo.Context, credentialID string) error {
+	if err := w.IssuerService.Revoke(ctx.Request().Context(), credentialID); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (w Wrapper) RenewCredential(ctx echo.Context) error {
+	request := RenewCredentialJSONRequestBody{}
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	result, err := w.IssuerService.Renew(ctx.Request().Context(), request.CredentialId, request.ExpirationDate, request.RevokeOriginal != nil && *request.RevokeOriginal)
+	if errors.Is(err, issuer.ErrNotRenewable) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) GetJobs(ctx echo.Context, params GetJobsParams) error {
+	var jobType string
+	if params.Type != nil {
+		jobType = *params.Type
+	}
+	return ctx.JSON(http.StatusOK, w.Jobs.List(jobType))
+}
+
+func (w Wrapper) GetJob(ctx echo.Context, id string) error {
+	result, ok := w.Jobs.Get(id)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, job.ErrNotFound.Error())
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) CancelJob(ctx echo.Context, id string) error {
+	if err := w.Jobs.Cancel(id); err != nil {
+		return jobError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (w Wrapper) RetryJob(ctx echo.Context, id string) error {
+	result, err := w.Jobs.Retry(ctx.Request().Context(), id)
+	if err != nil {
+		return jobError(err)
+	}
+	return ctx.JSON(http.StatusAccepted, result)
+}
+
+func (w Wrapper) GetIssuedCredentials(ctx echo.Context, params GetIssuedCredentialsParams) error {
+	filter := issuer.CredentialFilter{
+		CredentialTypes: strings.Split(params.CredentialTypes, ","),
+		IssuedAfter:     params.IssuedAfter,
+		IssuedBefore:    params.IssuedBefore,
+	}
+	if params.Issuer != nil {
+		filter.Issuer = *params.Issuer
+	}
+	if params.Holder != nil {
+		filter.Holder = *params.Holder
+	}
+	result, err := w.IssuerService.SearchIssuedCredentials(ctx.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) BulkRevokeCredentials(ctx echo.Context) error {
+	request := BulkRevokeCredentialsJSONRequestBody{}
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	result, err := w.IssuerService.BulkRevoke(ctx.Request().Context(), request)
+	if errors.Is(err, issuer.ErrInvalidRevocation) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) GetRevocations(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, w.IssuerService.Revocations())
+}
+
+func (w Wrapper) Inspect(ctx echo.Context) error {
+	data, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxWalletImportSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxWalletImportSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "input must not exceed 10 MiB")
+	}
+	result, err := w.Inspector.Inspect(ctx.Request().Context(), string(data))
+	if errors.Is(err, inspector.ErrInvalidInput) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) ListDiscoveryServices(ctx echo.Context) error {
+	services, err := w.Discovery.GetDiscoveryServices(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, services)
+}
+
+func (w Wrapper) GetDiscoveryService(ctx echo.Context, serviceID string) error {
+	definition, err := w.Discovery.GetDefinition(ctx.Request().Context(), serviceID)
+	if errors.Is(err, discovery.ErrServiceNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, definition)
+}
+
+func (w Wrapper) EvaluatePolicy(ctx echo.Context) error {
+	var request EvaluatePolicyJSONRequestBody
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	if request.Scope == "" || request.Subject == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "scope and subject are required")
+	}
+	result, err := w.Policy.Evaluate(ctx.Request().Context(), request)
+	switch {
+	case errors.Is(err, policy.ErrScopeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, policy.ErrInvalidPolicy):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, pe.ErrUnsupported):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case err != nil:
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (w Wrapper) Lint(ctx echo.Context) error {
+	var request LintJSONRequestBody
+	if err := ctx.Bind(&request); err != nil {
+		return err
+	}
+	if len(request.Files) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "at least one file is required")
+	}
+	return ctx.JSON(http.StatusOK, lint.Lint(request.Files))
+}
+
+func (w Wrapper) SearchDiscoveryService(ctx echo.Context, serviceID string, params SearchDiscoveryServiceParams) error {
+	query := discovery.SearchQuery{}
+	if params.OrganizationName != nil {
+		query.OrganizationName = *params.OrganizationName
+	}
+	if params.City != nil {
+		query.City = *params.City
+	}
+	if params.Ura != nil {
+		query.URA = *params.Ura
+	}
+	if params.CredentialType != nil {
+		query.CredentialType = *params.CredentialType
+	}
+	if params.Page != nil {
+		query.Page = *params.Page
+	}
+	if params.PageSize != nil {
+		query.PageSize = *params.PageSize
+	}
+	result, err := w.Discovery.Search(ctx.Request().Context(), serviceID, query)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// jobError maps job errors to the appropriate HTTP status: unknown jobs to 404, invalid state transitions to 409.
+func jobError(err error) error {
+	if errors.Is(err, job.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusConflict, err.Error())
+}
+
+// serviceError maps DID service validation errors to the appropriate HTTP status.
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, identity.ErrServiceNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, identity.ErrInvalidService):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return err
+	}
+}
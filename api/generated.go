@@ -6,20 +6,68 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	capture "github.com/nuts-foundation/nuts-admin/capture"
+	discovery "github.com/nuts-foundation/nuts-admin/discovery"
+	expiry "github.com/nuts-foundation/nuts-admin/expiry"
+	identity "github.com/nuts-foundation/nuts-admin/identity"
+	inspector "github.com/nuts-foundation/nuts-admin/inspector"
+	issuer "github.com/nuts-foundation/nuts-admin/issuer"
+	job "github.com/nuts-foundation/nuts-admin/job"
+	lint "github.com/nuts-foundation/nuts-admin/lint"
 	model "github.com/nuts-foundation/nuts-admin/model"
+	openid4vci "github.com/nuts-foundation/nuts-admin/openid4vci"
+	policy "github.com/nuts-foundation/nuts-admin/policy"
+	provisioning "github.com/nuts-foundation/nuts-admin/provisioning"
+	registration "github.com/nuts-foundation/nuts-admin/registration"
+	reload "github.com/nuts-foundation/nuts-admin/reload"
 	"github.com/oapi-codegen/runtime"
 )
 
+// BulkRevokeRequest Selects the issued credentials to revoke
+type BulkRevokeRequest = issuer.BulkRevokeRequest
+
+// BulkRevokeResult The credentials selected for revocation and the job that revokes them
+type BulkRevokeResult = issuer.BulkRevokeResult
+
 // Config Application configuration
 type Config struct {
 	CredentialProfiles []CredentialProfile `json:"credential_profiles"`
+
+	// CredentialRequests Whether credentials can be requested, which requires the url of nuts-admin to be configured.
+	CredentialRequests bool             `json:"credential_requests"`
+	ServiceProfiles    []ServiceProfile `json:"service_profiles"`
 }
 
+// ConfigReloadStatus Whether the config file is watched, and the result of the last reload
+type ConfigReloadStatus = reload.Report
+
 // CredentialProfile A credential profile for OpenID4VCI issuance
 type CredentialProfile = model.CredentialProfile
 
+// CredentialRequest A request for a credential from an issuer using OpenID4VCI
+type CredentialRequest = identity.CredentialRequest
+
+// CredentialRequestRecord A tracked OpenID4VCI credential request and its outcome
+type CredentialRequestRecord = openid4vci.Request
+
+// CredentialRequestResult The URL the user must be redirected to, to authorize the credential issuance
+type CredentialRequestResult = identity.CredentialRequestResult
+
+// DiscoveryHealthReport The health of the discovery service registrations of all subjects
+type DiscoveryHealthReport = registration.Report
+
+// DiscoverySearchPage A page of discovery service search results
+type DiscoverySearchPage = discovery.SearchPage
+
+// DiscoveryServiceDefinition A readable view of a discovery service definition
+type DiscoveryServiceDefinition = discovery.Definition
+
+// ExpiryReport Credentials that expire soon
+type ExpiryReport = expiry.Report
+
 // Identity An identity object
 type Identity struct {
 	// Did The DID associated with this identity
@@ -41,37 +89,360 @@ type IdentityDetails struct {
 	WalletCredentials []map[string]interface{} `json:"wallet_credentials"`
 }
 
+// ImportManifest Identities to provision
+type ImportManifest = provisioning.Manifest
+
+// InspectionReport A decoded and verified Verifiable Presentation or Verifiable Credential
+type InspectionReport = inspector.Report
+
+// IssueRequest A credential to issue
+type IssueRequest = issuer.IssueRequest
+
+// Job A long-running operation that processes items in the background
+type Job = job.Job
+
+// LintFile A discovery service definition or policy file to lint
+type LintFile = lint.File
+
+// LintReport The issues found in discovery service definitions and policy files
+type LintReport = lint.Report
+
+// PolicyEvaluation The requirements of a scope's organization presentation definition, evaluated against a subject's wallet
+type PolicyEvaluation = policy.Evaluation
+
+// PolicyEvaluationRequest Selects the scope and subject to evaluate
+type PolicyEvaluationRequest = policy.EvaluationRequest
+
+// ProxyCapture A proxied request to the Nuts node and its response, with redacted bodies
+type ProxyCapture = capture.Record
+
+// RenewResult A renewed credential, linked to the credential it replaces
+type RenewResult = issuer.RenewResult
+
+// RevocationRecord A credential revoked through nuts-admin
+type RevocationRecord = issuer.RevocationRecord
+
+// ServiceProfile Requirements for DID services of a specific type
+type ServiceProfile = model.ServiceProfile
+
+// ServiceRequest A service to be added to the DID documents of an identity
+type ServiceRequest struct {
+	// ServiceEndpoint Either a URL or an object with URLs (for compound services)
+	ServiceEndpoint interface{} `json:"serviceEndpoint"`
+	Type            string      `json:"type"`
+}
+
+// WalletImportResult The outcome of importing credentials into a wallet
+type WalletImportResult = identity.WalletImportResult
+
+// GetDiscoveryHealthParams defines parameters for GetDiscoveryHealth.
+type GetDiscoveryHealthParams struct {
+	// Refresh If true, the registrations are checked before returning the result.
+	Refresh *bool `form:"refresh,omitempty" json:"refresh,omitempty"`
+}
+
+// SearchDiscoveryServiceParams defines parameters for SearchDiscoveryService.
+type SearchDiscoveryServiceParams struct {
+	// OrganizationName Only return registrations with this organization name.
+	OrganizationName *string `form:"organizationName,omitempty" json:"organizationName,omitempty"`
+
+	// City Only return registrations with this organization city.
+	City *string `form:"city,omitempty" json:"city,omitempty"`
+
+	// Ura Only return registrations with this URA.
+	Ura *string `form:"ura,omitempty" json:"ura,omitempty"`
+
+	// CredentialType Only return registrations that contain a credential of this type.
+	CredentialType *string `form:"credentialType,omitempty" json:"credentialType,omitempty"`
+
+	// Page The 1-based page number.
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// PageSize The number of results per page (maximum 100).
+	PageSize *int `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+}
+
 // CreateIdentityJSONBody defines parameters for CreateIdentity.
 type CreateIdentityJSONBody struct {
 	Subject *string `json:"subject,omitempty"`
 }
 
+// DeactivateIdentityParams defines parameters for DeactivateIdentity.
+type DeactivateIdentityParams struct {
+	// Confirm Must be equal to the subject of the identity that is deactivated.
+	Confirm string `form:"confirm" json:"confirm"`
+}
+
+// ActivateDiscoveryServiceJSONBody defines parameters for ActivateDiscoveryService.
+type ActivateDiscoveryServiceJSONBody struct {
+	// RegistrationParameters Parameters that are added to the registration, e.g. the endpoints of the identity.
+	RegistrationParameters *map[string]interface{} `json:"registrationParameters,omitempty"`
+}
+
+// DeleteIdentityServiceParams defines parameters for DeleteIdentityService.
+type DeleteIdentityServiceParams struct {
+	// Confirm Must be equal to the ID of the service that is deleted.
+	Confirm string `form:"confirm" json:"confirm"`
+}
+
+// UploadCredentialJSONBody A JSON-LD credential, or a JWT credential as JSON string.
+type UploadCredentialJSONBody = map[string]interface{}
+
+// ImportWalletCredentialsJSONBody defines parameters for ImportWalletCredentials.
+type ImportWalletCredentialsJSONBody = map[string]interface{}
+
+// ImportIdentitiesParams defines parameters for ImportIdentities.
+type ImportIdentitiesParams struct {
+	// Rerun If true, the import is idempotent: existing subjects, credentials already in the wallet
+	// and active discovery services are skipped instead of reported as failure.
+	Rerun *bool `form:"rerun,omitempty" json:"rerun,omitempty"`
+}
+
 // GetIssuedCredentialsParams defines parameters for GetIssuedCredentials.
 type GetIssuedCredentialsParams struct {
 	// CredentialTypes A comma-separated list of credential types which are returned.
 	CredentialTypes string `form:"credentialTypes" json:"credentialTypes"`
+
+	// Issuer Only return credentials issued by this DID.
+	Issuer *string `form:"issuer,omitempty" json:"issuer,omitempty"`
+
+	// Holder Only return credentials issued to this DID.
+	Holder *string `form:"holder,omitempty" json:"holder,omitempty"`
+
+	// IssuedAfter Only return credentials issued at or after this time.
+	IssuedAfter *time.Time `form:"issuedAfter,omitempty" json:"issuedAfter,omitempty"`
+
+	// IssuedBefore Only return credentials issued before this time.
+	IssuedBefore *time.Time `form:"issuedBefore,omitempty" json:"issuedBefore,omitempty"`
+}
+
+// RenewCredentialJSONBody defines parameters for RenewCredential.
+type RenewCredentialJSONBody struct {
+	// CredentialId The ID of the credential to renew.
+	CredentialId string `json:"credential_id"`
+
+	// ExpirationDate The expiration date of the renewed credential.
+	// Defaults to the current time plus the validity period of the original credential.
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+
+	// RevokeOriginal If true, the original credential is revoked after the renewed credential was issued.
+	RevokeOriginal *bool `json:"revoke_original,omitempty"`
+}
+
+// GetJobsParams defines parameters for GetJobs.
+type GetJobsParams struct {
+	// Type Only return jobs of this type.
+	Type *string `form:"type,omitempty" json:"type,omitempty"`
+}
+
+// CompleteCredentialRequestParams defines parameters for CompleteCredentialRequest.
+type CompleteCredentialRequestParams struct {
+	// State The ID of the credential request.
+	State string `form:"state" json:"state"`
+
+	// Error The error code, if the issuance failed.
+	Error *string `form:"error,omitempty" json:"error,omitempty"`
+
+	// ErrorDescription The description of the error, if the issuance failed.
+	ErrorDescription *string `form:"error_description,omitempty" json:"error_description,omitempty"`
+}
+
+// LintJSONBody defines parameters for Lint.
+type LintJSONBody struct {
+	Files []LintFile `json:"files"`
 }
 
+// InspectJSONBody A JSON-LD presentation or credential, or a JWT as JSON string.
+type InspectJSONBody = map[string]interface{}
+
+// CreateCredentialProfileJSONRequestBody defines body for CreateCredentialProfile for application/json ContentType.
+type CreateCredentialProfileJSONRequestBody = CredentialProfile
+
+// UpdateCredentialProfileJSONRequestBody defines body for UpdateCredentialProfile for application/json ContentType.
+type UpdateCredentialProfileJSONRequestBody = CredentialProfile
+
 // CreateIdentityJSONRequestBody defines body for CreateIdentity for application/json ContentType.
 type CreateIdentityJSONRequestBody CreateIdentityJSONBody
 
+// ActivateDiscoveryServiceJSONRequestBody defines body for ActivateDiscoveryService for application/json ContentType.
+type ActivateDiscoveryServiceJSONRequestBody ActivateDiscoveryServiceJSONBody
+
+// RequestCredentialJSONRequestBody defines body for RequestCredential for application/json ContentType.
+type RequestCredentialJSONRequestBody = CredentialRequest
+
+// CreateIdentityServiceJSONRequestBody defines body for CreateIdentityService for application/json ContentType.
+type CreateIdentityServiceJSONRequestBody = ServiceRequest
+
+// UpdateIdentityServiceJSONRequestBody defines body for UpdateIdentityService for application/json ContentType.
+type UpdateIdentityServiceJSONRequestBody = ServiceRequest
+
+// UploadCredentialJSONRequestBody defines body for UploadCredential for application/json ContentType.
+type UploadCredentialJSONRequestBody = UploadCredentialJSONBody
+
+// ImportWalletCredentialsJSONRequestBody defines body for ImportWalletCredentials for application/json ContentType.
+type ImportWalletCredentialsJSONRequestBody = ImportWalletCredentialsJSONBody
+
+// ImportIdentitiesJSONRequestBody defines body for ImportIdentities for application/json ContentType.
+type ImportIdentitiesJSONRequestBody = ImportManifest
+
+// IssueCredentialJSONRequestBody defines body for IssueCredential for application/json ContentType.
+type IssueCredentialJSONRequestBody = IssueRequest
+
+// RenewCredentialJSONRequestBody defines body for RenewCredential for application/json ContentType.
+type RenewCredentialJSONRequestBody RenewCredentialJSONBody
+
+// BulkRevokeCredentialsJSONRequestBody defines body for BulkRevokeCredentials for application/json ContentType.
+type BulkRevokeCredentialsJSONRequestBody = BulkRevokeRequest
+
+// EvaluatePolicyJSONRequestBody defines body for EvaluatePolicy for application/json ContentType.
+type EvaluatePolicyJSONRequestBody = PolicyEvaluationRequest
+
+// LintJSONRequestBody defines body for Lint for application/json ContentType.
+type LintJSONRequestBody LintJSONBody
+
+// InspectJSONRequestBody defines body for Inspect for application/json ContentType.
+type InspectJSONRequestBody = InspectJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /api/alerts/expiring)
+	GetExpiringCredentials(ctx echo.Context) error
+
+	// (POST /api/alerts/expiring)
+	ScanExpiringCredentials(ctx echo.Context) error
+
 	// (GET /api/config)
 	GetConfig(ctx echo.Context) error
 
+	// (GET /api/config/reload-status)
+	GetConfigReloadStatus(ctx echo.Context) error
+
+	// (GET /api/credential-profiles)
+	ListCredentialProfiles(ctx echo.Context) error
+
+	// (POST /api/credential-profiles)
+	CreateCredentialProfile(ctx echo.Context) error
+
+	// (DELETE /api/credential-profiles/{id})
+	DeleteCredentialProfile(ctx echo.Context, id string) error
+
+	// (GET /api/credential-profiles/{id})
+	GetCredentialProfile(ctx echo.Context, id string) error
+
+	// (PUT /api/credential-profiles/{id})
+	UpdateCredentialProfile(ctx echo.Context, id string) error
+
+	// (GET /api/debug/proxy)
+	GetProxyCaptures(ctx echo.Context) error
+
+	// (GET /api/discovery)
+	ListDiscoveryServices(ctx echo.Context) error
+
+	// (GET /api/discovery/health)
+	GetDiscoveryHealth(ctx echo.Context, params GetDiscoveryHealthParams) error
+
+	// (GET /api/discovery/{serviceID})
+	GetDiscoveryService(ctx echo.Context, serviceID string) error
+
+	// (GET /api/discovery/{serviceID}/search)
+	SearchDiscoveryService(ctx echo.Context, serviceID string, params SearchDiscoveryServiceParams) error
+
 	// (GET /api/id)
 	GetIdentities(ctx echo.Context) error
 
 	// (POST /api/id)
 	CreateIdentity(ctx echo.Context) error
 
+	// (DELETE /api/id/{did})
+	DeactivateIdentity(ctx echo.Context, did string, params DeactivateIdentityParams) error
+
 	// (GET /api/id/{did})
 	GetIdentity(ctx echo.Context, did string) error
 
+	// (GET /api/id/{did}/credential-requests)
+	ListCredentialRequests(ctx echo.Context, did string) error
+
+	// (DELETE /api/id/{did}/discovery/{serviceID})
+	DeactivateDiscoveryService(ctx echo.Context, did string, serviceID string) error
+
+	// (POST /api/id/{did}/discovery/{serviceID})
+	ActivateDiscoveryService(ctx echo.Context, did string, serviceID string) error
+
+	// (POST /api/id/{did}/request-credential)
+	RequestCredential(ctx echo.Context, did string) error
+
+	// (GET /api/id/{did}/service)
+	GetIdentityServices(ctx echo.Context, did string) error
+
+	// (POST /api/id/{did}/service)
+	CreateIdentityService(ctx echo.Context, did string) error
+
+	// (DELETE /api/id/{did}/service/{serviceID})
+	DeleteIdentityService(ctx echo.Context, did string, serviceID string, params DeleteIdentityServiceParams) error
+
+	// (PUT /api/id/{did}/service/{serviceID})
+	UpdateIdentityService(ctx echo.Context, did string, serviceID string) error
+
+	// (POST /api/id/{did}/verificationmethod)
+	AddVerificationMethod(ctx echo.Context, did string) error
+
+	// (POST /api/id/{did}/wallet)
+	UploadCredential(ctx echo.Context, did string) error
+
+	// (GET /api/id/{did}/wallet/export)
+	ExportWalletCredentials(ctx echo.Context, did string) error
+
+	// (POST /api/id/{did}/wallet/import)
+	ImportWalletCredentials(ctx echo.Context, did string) error
+
+	// (DELETE /api/id/{did}/wallet/{credentialID})
+	RemoveWalletCredential(ctx echo.Context, did string, credentialID string) error
+
+	// (POST /api/import/identities)
+	ImportIdentities(ctx echo.Context, params ImportIdentitiesParams) error
+
+	// (GET /api/issuer/revocations)
+	GetRevocations(ctx echo.Context) error
+
 	// (GET /api/issuer/vc)
 	GetIssuedCredentials(ctx echo.Context, params GetIssuedCredentialsParams) error
+
+	// (POST /api/issuer/vc)
+	IssueCredential(ctx echo.Context) error
+
+	// (POST /api/issuer/vc/renew)
+	RenewCredential(ctx echo.Context) error
+
+	// (POST /api/issuer/vc/revoke)
+	BulkRevokeCredentials(ctx echo.Context) error
+
+	// (DELETE /api/issuer/vc/{credentialID})
+	RevokeCredential(ctx echo.Context, credentialID string) error
+
+	// (GET /api/jobs)
+	GetJobs(ctx echo.Context, params GetJobsParams) error
+
+	// (GET /api/jobs/{id})
+	GetJob(ctx echo.Context, id string) error
+
+	// (POST /api/jobs/{id}/cancel)
+	CancelJob(ctx echo.Context, id string) error
+
+	// (POST /api/jobs/{id}/retry)
+	RetryJob(ctx echo.Context, id string) error
+
+	// (GET /api/openid4vci/callback)
+	CompleteCredentialRequest(ctx echo.Context, params CompleteCredentialRequestParams) error
+
+	// (POST /api/policy/evaluate)
+	EvaluatePolicy(ctx echo.Context) error
+
+	// (POST /api/tools/lint)
+	Lint(ctx echo.Context) error
+
+	// (POST /api/tools/verify)
+	Inspect(ctx echo.Context) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -79,6 +450,24 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetExpiringCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) GetExpiringCredentials(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetExpiringCredentials(ctx)
+	return err
+}
+
+// ScanExpiringCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) ScanExpiringCredentials(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScanExpiringCredentials(ctx)
+	return err
+}
+
 // GetConfig converts echo context to params.
 func (w *ServerInterfaceWrapper) GetConfig(ctx echo.Context) error {
 	var err error
@@ -88,6 +477,193 @@ func (w *ServerInterfaceWrapper) GetConfig(ctx echo.Context) error {
 	return err
 }
 
+// GetConfigReloadStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) GetConfigReloadStatus(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetConfigReloadStatus(ctx)
+	return err
+}
+
+// ListCredentialProfiles converts echo context to params.
+func (w *ServerInterfaceWrapper) ListCredentialProfiles(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListCredentialProfiles(ctx)
+	return err
+}
+
+// CreateCredentialProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateCredentialProfile(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateCredentialProfile(ctx)
+	return err
+}
+
+// DeleteCredentialProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteCredentialProfile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteCredentialProfile(ctx, id)
+	return err
+}
+
+// GetCredentialProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCredentialProfile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCredentialProfile(ctx, id)
+	return err
+}
+
+// UpdateCredentialProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateCredentialProfile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateCredentialProfile(ctx, id)
+	return err
+}
+
+// GetProxyCaptures converts echo context to params.
+func (w *ServerInterfaceWrapper) GetProxyCaptures(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetProxyCaptures(ctx)
+	return err
+}
+
+// ListDiscoveryServices converts echo context to params.
+func (w *ServerInterfaceWrapper) ListDiscoveryServices(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListDiscoveryServices(ctx)
+	return err
+}
+
+// GetDiscoveryHealth converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDiscoveryHealth(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDiscoveryHealthParams
+	// ------------- Optional query parameter "refresh" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "refresh", ctx.QueryParams(), &params.Refresh)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter refresh: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetDiscoveryHealth(ctx, params)
+	return err
+}
+
+// GetDiscoveryService converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDiscoveryService(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "serviceID" -------------
+	var serviceID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "serviceID", ctx.Param("serviceID"), &serviceID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter serviceID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetDiscoveryService(ctx, serviceID)
+	return err
+}
+
+// SearchDiscoveryService converts echo context to params.
+func (w *ServerInterfaceWrapper) SearchDiscoveryService(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "serviceID" -------------
+	var serviceID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "serviceID", ctx.Param("serviceID"), &serviceID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter serviceID: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchDiscoveryServiceParams
+	// ------------- Optional query parameter "organizationName" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "organizationName", ctx.QueryParams(), &params.OrganizationName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter organizationName: %s", err))
+	}
+
+	// ------------- Optional query parameter "city" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "city", ctx.QueryParams(), &params.City)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter city: %s", err))
+	}
+
+	// ------------- Optional query parameter "ura" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "ura", ctx.QueryParams(), &params.Ura)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ura: %s", err))
+	}
+
+	// ------------- Optional query parameter "credentialType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "credentialType", ctx.QueryParams(), &params.CredentialType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter credentialType: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SearchDiscoveryService(ctx, serviceID, params)
+	return err
+}
+
 // GetIdentities converts echo context to params.
 func (w *ServerInterfaceWrapper) GetIdentities(ctx echo.Context) error {
 	var err error
@@ -106,6 +682,28 @@ func (w *ServerInterfaceWrapper) CreateIdentity(ctx echo.Context) error {
 	return err
 }
 
+// DeactivateIdentity converts echo context to params.
+func (w *ServerInterfaceWrapper) DeactivateIdentity(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeactivateIdentityParams
+	// ------------- Required query parameter "confirm" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "confirm", ctx.QueryParams(), &params.Confirm)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter confirm: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeactivateIdentity(ctx, did, params)
+	return err
+}
+
 // GetIdentity converts echo context to params.
 func (w *ServerInterfaceWrapper) GetIdentity(ctx echo.Context) error {
 	var err error
@@ -119,6 +717,251 @@ func (w *ServerInterfaceWrapper) GetIdentity(ctx echo.Context) error {
 	return err
 }
 
+// ListCredentialRequests converts echo context to params.
+func (w *ServerInterfaceWrapper) ListCredentialRequests(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListCredentialRequests(ctx, did)
+	return err
+}
+
+// DeactivateDiscoveryService converts echo context to params.
+func (w *ServerInterfaceWrapper) DeactivateDiscoveryService(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// ------------- Path parameter "serviceID" -------------
+	var serviceID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "serviceID", ctx.Param("serviceID"), &serviceID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter serviceID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeactivateDiscoveryService(ctx, did, serviceID)
+	return err
+}
+
+// ActivateDiscoveryService converts echo context to params.
+func (w *ServerInterfaceWrapper) ActivateDiscoveryService(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// ------------- Path parameter "serviceID" -------------
+	var serviceID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "serviceID", ctx.Param("serviceID"), &serviceID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter serviceID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ActivateDiscoveryService(ctx, did, serviceID)
+	return err
+}
+
+// RequestCredential converts echo context to params.
+func (w *ServerInterfaceWrapper) RequestCredential(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RequestCredential(ctx, did)
+	return err
+}
+
+// GetIdentityServices converts echo context to params.
+func (w *ServerInterfaceWrapper) GetIdentityServices(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetIdentityServices(ctx, did)
+	return err
+}
+
+// CreateIdentityService converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateIdentityService(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateIdentityService(ctx, did)
+	return err
+}
+
+// DeleteIdentityService converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteIdentityService(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// ------------- Path parameter "serviceID" -------------
+	var serviceID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "serviceID", ctx.Param("serviceID"), &serviceID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter serviceID: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteIdentityServiceParams
+	// ------------- Required query parameter "confirm" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "confirm", ctx.QueryParams(), &params.Confirm)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter confirm: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteIdentityService(ctx, did, serviceID, params)
+	return err
+}
+
+// UpdateIdentityService converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateIdentityService(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// ------------- Path parameter "serviceID" -------------
+	var serviceID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "serviceID", ctx.Param("serviceID"), &serviceID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter serviceID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateIdentityService(ctx, did, serviceID)
+	return err
+}
+
+// AddVerificationMethod converts echo context to params.
+func (w *ServerInterfaceWrapper) AddVerificationMethod(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AddVerificationMethod(ctx, did)
+	return err
+}
+
+// UploadCredential converts echo context to params.
+func (w *ServerInterfaceWrapper) UploadCredential(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UploadCredential(ctx, did)
+	return err
+}
+
+// ExportWalletCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportWalletCredentials(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ExportWalletCredentials(ctx, did)
+	return err
+}
+
+// ImportWalletCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportWalletCredentials(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImportWalletCredentials(ctx, did)
+	return err
+}
+
+// RemoveWalletCredential converts echo context to params.
+func (w *ServerInterfaceWrapper) RemoveWalletCredential(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "did" -------------
+	var did string
+
+	did = ctx.Param("did")
+
+	// ------------- Path parameter "credentialID" -------------
+	var credentialID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "credentialID", ctx.Param("credentialID"), &credentialID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter credentialID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RemoveWalletCredential(ctx, did, credentialID)
+	return err
+}
+
+// ImportIdentities converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportIdentities(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportIdentitiesParams
+	// ------------- Optional query parameter "rerun" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rerun", ctx.QueryParams(), &params.Rerun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rerun: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImportIdentities(ctx, params)
+	return err
+}
+
+// GetRevocations converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRevocations(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRevocations(ctx)
+	return err
+}
+
 // GetIssuedCredentials converts echo context to params.
 func (w *ServerInterfaceWrapper) GetIssuedCredentials(ctx echo.Context) error {
 	var err error
@@ -132,11 +975,207 @@ func (w *ServerInterfaceWrapper) GetIssuedCredentials(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter credentialTypes: %s", err))
 	}
 
+	// ------------- Optional query parameter "issuer" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "issuer", ctx.QueryParams(), &params.Issuer)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter issuer: %s", err))
+	}
+
+	// ------------- Optional query parameter "holder" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "holder", ctx.QueryParams(), &params.Holder)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter holder: %s", err))
+	}
+
+	// ------------- Optional query parameter "issuedAfter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "issuedAfter", ctx.QueryParams(), &params.IssuedAfter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter issuedAfter: %s", err))
+	}
+
+	// ------------- Optional query parameter "issuedBefore" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "issuedBefore", ctx.QueryParams(), &params.IssuedBefore)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter issuedBefore: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetIssuedCredentials(ctx, params)
 	return err
 }
 
+// IssueCredential converts echo context to params.
+func (w *ServerInterfaceWrapper) IssueCredential(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.IssueCredential(ctx)
+	return err
+}
+
+// RenewCredential converts echo context to params.
+func (w *ServerInterfaceWrapper) RenewCredential(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RenewCredential(ctx)
+	return err
+}
+
+// BulkRevokeCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) BulkRevokeCredentials(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.BulkRevokeCredentials(ctx)
+	return err
+}
+
+// RevokeCredential converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeCredential(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "credentialID" -------------
+	var credentialID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "credentialID", ctx.Param("credentialID"), &credentialID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter credentialID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RevokeCredential(ctx, credentialID)
+	return err
+}
+
+// GetJobs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJobs(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetJobsParams
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", ctx.QueryParams(), &params.Type)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter type: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJobs(ctx, params)
+	return err
+}
+
+// GetJob converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJob(ctx, id)
+	return err
+}
+
+// CancelJob converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CancelJob(ctx, id)
+	return err
+}
+
+// RetryJob converts echo context to params.
+func (w *ServerInterfaceWrapper) RetryJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RetryJob(ctx, id)
+	return err
+}
+
+// CompleteCredentialRequest converts echo context to params.
+func (w *ServerInterfaceWrapper) CompleteCredentialRequest(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CompleteCredentialRequestParams
+	// ------------- Required query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "state", ctx.QueryParams(), &params.State)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter state: %s", err))
+	}
+
+	// ------------- Optional query parameter "error" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error", ctx.QueryParams(), &params.Error)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter error: %s", err))
+	}
+
+	// ------------- Optional query parameter "error_description" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error_description", ctx.QueryParams(), &params.ErrorDescription)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter error_description: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CompleteCredentialRequest(ctx, params)
+	return err
+}
+
+// EvaluatePolicy converts echo context to params.
+func (w *ServerInterfaceWrapper) EvaluatePolicy(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.EvaluatePolicy(ctx)
+	return err
+}
+
+// Lint converts echo context to params.
+func (w *ServerInterfaceWrapper) Lint(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Lint(ctx)
+	return err
+}
+
+// Inspect converts echo context to params.
+func (w *ServerInterfaceWrapper) Inspect(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Inspect(ctx)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -165,10 +1204,51 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/api/alerts/expiring", wrapper.GetExpiringCredentials)
+	router.POST(baseURL+"/api/alerts/expiring", wrapper.ScanExpiringCredentials)
 	router.GET(baseURL+"/api/config", wrapper.GetConfig)
+	router.GET(baseURL+"/api/config/reload-status", wrapper.GetConfigReloadStatus)
+	router.GET(baseURL+"/api/credential-profiles", wrapper.ListCredentialProfiles)
+	router.POST(baseURL+"/api/credential-profiles", wrapper.CreateCredentialProfile)
+	router.DELETE(baseURL+"/api/credential-profiles/:id", wrapper.DeleteCredentialProfile)
+	router.GET(baseURL+"/api/credential-profiles/:id", wrapper.GetCredentialProfile)
+	router.PUT(baseURL+"/api/credential-profiles/:id", wrapper.UpdateCredentialProfile)
+	router.GET(baseURL+"/api/debug/proxy", wrapper.GetProxyCaptures)
+	router.GET(baseURL+"/api/discovery", wrapper.ListDiscoveryServices)
+	router.GET(baseURL+"/api/discovery/health", wrapper.GetDiscoveryHealth)
+	router.GET(baseURL+"/api/discovery/:serviceID", wrapper.GetDiscoveryService)
+	router.GET(baseURL+"/api/discovery/:serviceID/search", wrapper.SearchDiscoveryService)
 	router.GET(baseURL+"/api/id", wrapper.GetIdentities)
 	router.POST(baseURL+"/api/id", wrapper.CreateIdentity)
+	router.DELETE(baseURL+"/api/id/:did", wrapper.DeactivateIdentity)
 	router.GET(baseURL+"/api/id/:did", wrapper.GetIdentity)
+	router.GET(baseURL+"/api/id/:did/credential-requests", wrapper.ListCredentialRequests)
+	router.DELETE(baseURL+"/api/id/:did/discovery/:serviceID", wrapper.DeactivateDiscoveryService)
+	router.POST(baseURL+"/api/id/:did/discovery/:serviceID", wrapper.ActivateDiscoveryService)
+	router.POST(baseURL+"/api/id/:did/request-credential", wrapper.RequestCredential)
+	router.GET(baseURL+"/api/id/:did/service", wrapper.GetIdentityServices)
+	router.POST(baseURL+"/api/id/:did/service", wrapper.CreateIdentityService)
+	router.DELETE(baseURL+"/api/id/:did/service/:serviceID", wrapper.DeleteIdentityService)
+	router.PUT(baseURL+"/api/id/:did/service/:serviceID", wrapper.UpdateIdentityService)
+	router.POST(baseURL+"/api/id/:did/verificationmethod", wrapper.AddVerificationMethod)
+	router.POST(baseURL+"/api/id/:did/wallet", wrapper.UploadCredential)
+	router.GET(baseURL+"/api/id/:did/wallet/export", wrapper.ExportWalletCredentials)
+	router.POST(baseURL+"/api/id/:did/wallet/import", wrapper.ImportWalletCredentials)
+	router.DELETE(baseURL+"/api/id/:did/wallet/:credentialID", wrapper.RemoveWalletCredential)
+	router.POST(baseURL+"/api/import/identities", wrapper.ImportIdentities)
+	router.GET(baseURL+"/api/issuer/revocations", wrapper.GetRevocations)
 	router.GET(baseURL+"/api/issuer/vc", wrapper.GetIssuedCredentials)
+	router.POST(baseURL+"/api/issuer/vc", wrapper.IssueCredential)
+	router.POST(baseURL+"/api/issuer/vc/renew", wrapper.RenewCredential)
+	router.POST(baseURL+"/api/issuer/vc/revoke", wrapper.BulkRevokeCredentials)
+	router.DELETE(baseURL+"/api/issuer/vc/:credentialID", wrapper.RevokeCredential)
+	router.GET(baseURL+"/api/jobs", wrapper.GetJobs)
+	router.GET(baseURL+"/api/jobs/:id", wrapper.GetJob)
+	router.POST(baseURL+"/api/jobs/:id/cancel", wrapper.CancelJob)
+	router.POST(baseURL+"/api/jobs/:id/retry", wrapper.RetryJob)
+	router.GET(baseURL+"/api/openid4vci/callback", wrapper.CompleteCredentialRequest)
+	router.POST(baseURL+"/api/policy/evaluate", wrapper.EvaluatePolicy)
+	router.POST(baseURL+"/api/tools/lint", wrapper.Lint)
+	router.POST(baseURL+"/api/tools/verify", wrapper.Inspect)
 
 }
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nuts-foundation/nuts-admin/oidc"
+)
+
+// HTTPRequestDoer performs HTTP requests. It's what the clients of go-nuts-client use to call the Nuts node (see their WithHTTPClient option).
+type HTTPRequestDoer interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+// caller is the user on whose behalf nuts-admin calls the Nuts node.
+type caller struct {
+	roles []string
+	ip    string
+}
+
+type callerKey struct{}
+
+// RecordCaller is middleware that stores the roles and IP address of the user in the context of the request.
+// The calls to the Nuts node made with that context, also by the jobs the request starts, are then authorized (see Proxy.NodeClient).
+func RecordCaller(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := c.Request()
+		ctx := context.WithValue(request.Context(), callerKey{}, caller{roles: oidc.Roles(c), ip: c.RealIP()})
+		c.SetRequest(request.WithContext(ctx))
+		return next(c)
+	}
+}
+
+// NodeClient returns a client for the Nuts node at the given address, which authorizes the calls the typed endpoints make
+// as if they were proxied: the user needs the role of every route that matches the call, and the request body must pass
+// the schema and rate limit of each of them. Calls that don't match a route are allowed, since the routes only restrict the proxy in that respect.
+// Calls that aren't made on behalf of a user (see RecordCaller), e.g. by the background monitors, aren't checked.
+func (p *Proxy) NodeClient(nodeAddress *url.URL, next HTTPRequestDoer) HTTPRequestDoer {
+	return nodeClient{
+		proxy:    p,
+		basePath: strings.TrimSuffix(nodeAddress.EscapedPath(), "/"),
+		next:     next,
+	}
+}
+
+type nodeClient struct {
+	proxy    *Proxy
+	basePath string
+	next     HTTPRequestDoer
+}
+
+func (n nodeClient) Do(request *http.Request) (*http.Response, error) {
+	current, ok := request.Context().Value(callerKey{}).(caller)
+	if !ok {
+		return n.next.Do(request)
+	}
+	path, _, err := canonicalProxyPath(strings.TrimPrefix(request.URL.EscapedPath(), n.basePath))
+	if err != nil {
+		return nil, err
+	}
+	routes := n.proxy.settings.Load().match(request.Method, path)
+	if len(routes) == 0 {
+		return n.next.Do(request)
+	}
+	var body []byte
+	if request.Body != nil {
+		if body, err = io.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		_ = request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	for _, route := range routes {
+		if err := route.authorize(current.roles, current.ip, body); err != nil {
+			return nil, err
+		}
+	}
+	return n.next.Do(request)
+}
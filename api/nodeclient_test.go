@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy_NodeClient(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		_, _ = writer.Write([]byte(request.Method + " " + request.URL.EscapedPath() + " " + string(body)))
+	}))
+	defer node.Close()
+	nodeURL, _ := url.Parse(node.URL + "/nuts")
+	proxy, err := NewProxy(ProxyConfig{Routes: []ProxyRoute{
+		{Method: http.MethodPost, Path: "/internal/vcr/v2/issuer/vc", Schema: map[string]interface{}{"type": "object", "required": []interface{}{"type"}}},
+		{Method: http.MethodDelete, Path: "/internal/vcr/v2/issuer/vc/[^/]+", Role: "issuer"},
+		{Method: http.MethodPut, Path: "/internal/vdr/v2/subject/did:web:example.com:8080/service/[^/]+", Role: "admin"},
+		{Method: http.MethodGet, Path: "/internal/vcr/v2/issuer/vc/search", RateLimit: RateLimit{Rate: 0.001, Burst: 1}},
+	}})
+	require.NoError(t, err)
+	client := proxy.NodeClient(nodeURL, http.DefaultClient)
+	call := func(ctx context.Context, method string, path string, body string) (string, error) {
+		request, _ := http.NewRequestWithContext(ctx, method, nodeURL.String()+path, strings.NewReader(body))
+		response, err := client.Do(request)
+		if err != nil {
+			return "", err
+		}
+		defer response.Body.Close()
+		data, _ := io.ReadAll(response.Body)
+		return string(data), nil
+	}
+	user := func(roles ...string) context.Context {
+		return context.WithValue(context.Background(), callerKey{}, caller{roles: roles, ip: "192.0.2.1"})
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		response, err := call(user(), http.MethodPost, "/internal/vcr/v2/issuer/vc", `{"type":"NutsOrganizationCredential"}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, `POST /nuts/internal/vcr/v2/issuer/vc {"type":"NutsOrganizationCredential"}`, response)
+	})
+	t.Run("request body doesn't match schema", func(t *testing.T) {
+		_, err := call(user(), http.MethodPost, "/internal/vcr/v2/issuer/vc", `{}`)
+
+		assert.ErrorContains(t, err, "$.type: is required")
+	})
+	t.Run("missing role", func(t *testing.T) {
+		_, err := call(user(), http.MethodDelete, "/internal/vcr/v2/issuer/vc/did:web:example.com%231", "")
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusForbidden, httpErr.Code)
+		assert.Contains(t, httpErr.Message, "requires role issuer")
+	})
+	t.Run("role", func(t *testing.T) {
+		_, err := call(user("issuer"), http.MethodDelete, "/internal/vcr/v2/issuer/vc/did:web:example.com%231", "")
+
+		assert.NoError(t, err)
+	})
+	t.Run("path is decoded", func(t *testing.T) {
+		_, err := call(user(), http.MethodPut, "/internal/vdr/v2/subject/did:web:example.com%3A8080/service/1", "{}")
+
+		assert.ErrorContains(t, err, "requires role admin")
+	})
+	t.Run("rate limited", func(t *testing.T) {
+		_, err := call(user(), http.MethodGet, "/internal/vcr/v2/issuer/vc/search", "")
+		require.NoError(t, err)
+		_, err = call(user(), http.MethodGet, "/internal/vcr/v2/issuer/vc/search", "")
+
+		assert.ErrorContains(t, err, "Rate limit")
+	})
+	t.Run("no matching route", func(t *testing.T) {
+		_, err := call(user(), http.MethodGet, "/internal/vdr/v2/subject", "")
+
+		assert.NoError(t, err)
+	})
+	t.Run("not on behalf of a user", func(t *testing.T) {
+		_, err := call(context.Background(), http.MethodDelete, "/internal/vcr/v2/issuer/vc/1", "")
+
+		assert.NoError(t, err)
+	})
+	t.Run("caller is recorded", func(t *testing.T) {
+		e := echo.New()
+		e.Use(RecordCaller)
+		e.DELETE("/api/issuer/vc/:id", func(c echo.Context) error {
+			if _, err := call(c.Request().Context(), http.MethodDelete, "/internal/vcr/v2/issuer/vc/"+c.Param("id"), ""); err != nil {
+				return err
+			}
+			return c.NoContent(http.StatusNoContent)
+		})
+		recorder := httptest.NewRecorder()
+
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/issuer/vc/1", nil))
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
@@ -1,111 +1,434 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/nuts-foundation/nuts-admin/capture"
+	"github.com/nuts-foundation/nuts-admin/jsonschema"
+	"github.com/nuts-foundation/nuts-admin/oidc"
+	"github.com/nuts-foundation/nuts-admin/validation"
 	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
 )
 
+// ProxyConfig configures which endpoints of the Nuts node may be called through the proxy.
+// The proxy is deprecated: the web application uses the typed endpoints of the API instead.
+type ProxyConfig struct {
+	// Enabled controls whether the proxy is available. If false, nothing is passed through to the Nuts node's internal API.
+	Enabled bool         `koanf:"enabled"`
+	Routes  []ProxyRoute `koanf:"routes"`
+	// MaxBodySize is the maximum size in bytes of proxied request bodies.
+	MaxBodySize int `koanf:"maxbodysize"`
+	// Capture configures the capture of proxied requests and responses for debugging.
+	Capture capture.Config `koanf:"capture"`
+}
+
+// ProxyRoute allows requests to an endpoint of the Nuts node.
+type ProxyRoute struct {
+	Method string `koanf:"method"`
+	// Path is a regular expression the (decoded and canonicalized) path on the Nuts node must match entirely.
+	Path string `koanf:"path"`
+	// Role is the role the user must have (see oidc.rolesclaim). If empty, any authenticated user may call the endpoint.
+	Role string `koanf:"role"`
+	// Schema is a JSON Schema the request body must match. If empty, the request body isn't validated.
+	Schema map[string]interface{} `koanf:"schema"`
+	// RateLimit limits the number of requests per client, identified by its IP address (see echo.Echo.IPExtractor).
+	RateLimit RateLimit `koanf:"ratelimit"`
+}
+
+type RateLimit struct {
+	// Rate is the number of requests per second that is allowed. If zero, requests aren't limited.
+	Rate float64 `koanf:"rate"`
+	// Burst is the number of requests that is allowed at once. Defaults to the rate (rounded up).
+	Burst int `koanf:"burst"`
+}
+
 type proxyRoute struct {
-	method       string
-	path         string
+	ProxyRoute
 	compiledPath *regexp.Regexp
+	schema       *jsonschema.Schema
+	limiter      middleware.RateLimiterStore
 }
 
 const proxyPath = "/api/proxy/"
 
-func init() {
-	for i := range allowedProxyRoutes {
-		allowedProxyRoutes[i].compiledPath = regexp.MustCompile(allowedProxyRoutes[i].path)
-	}
-}
-
-var allowedProxyRoutes = []proxyRoute{
-	// List Discovery Services
-	{
-		method: http.MethodGet,
-		path:   "/internal/discovery/v1",
-	},
-	// Search VPs on a Discovery Service
-	{
-		method: http.MethodGet,
-		path:   "/internal/discovery/v1/([a-z-A-Z0-9_\\-\\:\\.%]+)",
-	},
-	// Activate Discovery Services for a DID
-	{
-		method: http.MethodPost,
-		path:   "/internal/discovery/v1/([a-z-A-Z0-9_\\-\\:\\.%]+)/([a-z-A-Z0-9_\\-\\:\\.%]+)",
-	},
-	// Deactivate Discovery Services for a DID
-	{
-		method: http.MethodDelete,
-		path:   "/internal/discovery/v1/([a-z-A-Z0-9_\\-\\:\\.%]+)/([a-z-A-Z0-9_\\-\\:\\.%]+)",
-	},
-	// Issue Verifiable Credentials
-	{
-		method: http.MethodPost,
-		path:   "/internal/vcr/v2/issuer/vc",
-	},
-	// Search for issued Verifiable Credentials
-	{
-		method: http.MethodGet,
-		path:   "/internal/vcr/v2/issuer/vc/search",
-	},
-	// Load Verifiable Credential into wallet
-	{
-		method: http.MethodPost,
-		path:   "/internal/vcr/v2/holder/([a-z-A-Z0-9_\\-\\:\\.%]+)/vc",
-	},
-	// Delete Verifiable Credentials from wallet
-	{
-		method: http.MethodDelete,
-		path:   "/internal/vcr/v2/holder/([a-z-A-Z0-9_\\-\\:\\.%]+)/vc/(.*)",
-	},
-	// Request OpenID4VCI credential issuance
-	{
-		method: http.MethodPost,
-		path:   "/internal/auth/v2/([a-z-A-Z0-9_\\-\\:\\.%]+)/request-credential",
-	},
-	// Revoke Verifiable Credential
-	{
-		method: http.MethodDelete,
-		path:   "/internal/vcr/v2/issuer/vc/(.*)",
-	},
+// DefaultProxyConfig returns the routes the web application used to call through the proxy.
+func DefaultProxyConfig() ProxyConfig {
+	return ProxyConfig{
+		Enabled:     true,
+		MaxBodySize: 1024 * 1024,
+		Capture:     capture.DefaultConfig(),
+		Routes: []ProxyRoute{
+			// List Discovery Services
+			{
+				Method: http.MethodGet,
+				Path:   "/internal/discovery/v1",
+			},
+			// Search VPs on a Discovery Service
+			{
+				Method: http.MethodGet,
+				Path:   "/internal/discovery/v1/([a-z-A-Z0-9_\\-\\:\\.%]+)",
+			},
+			// Activate Discovery Services for a DID
+			{
+				Method: http.MethodPost,
+				Path:   "/internal/discovery/v1/([a-z-A-Z0-9_\\-\\:\\.%]+)/([a-z-A-Z0-9_\\-\\:\\.%]+)",
+			},
+			// Deactivate Discovery Services for a DID
+			{
+				Method: http.MethodDelete,
+				Path:   "/internal/discovery/v1/([a-z-A-Z0-9_\\-\\:\\.%]+)/([a-z-A-Z0-9_\\-\\:\\.%]+)",
+			},
+			// Issue Verifiable Credentials
+			{
+				Method: http.MethodPost,
+				Path:   "/internal/vcr/v2/issuer/vc",
+			},
+			// Search for issued Verifiable Credentials
+			{
+				Method: http.MethodGet,
+				Path:   "/internal/vcr/v2/issuer/vc/search",
+			},
+			// Load Verifiable Credential into wallet
+			{
+				Method: http.MethodPost,
+				Path:   "/internal/vcr/v2/holder/([a-z-A-Z0-9_\\-\\:\\.%]+)/vc",
+			},
+			// Delete Verifiable Credentials from wallet
+			{
+				Method: http.MethodDelete,
+				Path:   "/internal/vcr/v2/holder/([a-z-A-Z0-9_\\-\\:\\.%]+)/vc/([^/]+)",
+			},
+			// Revoke Verifiable Credential
+			{
+				Method: http.MethodDelete,
+				Path:   "/internal/vcr/v2/issuer/vc/([^/]+)",
+			},
+		},
+	}
+}
+
+// Validate checks the regular expressions and schemas of the routes, and that no two routes have the same method and path.
+// The routes are checked even if the proxy is disabled, since they also apply to the calls of the typed endpoints (see Proxy.NodeClient).
+// The returned error joins all problems (see errors.Join).
+func (c ProxyConfig) Validate() error {
+	var errs []error
+	if c.Enabled {
+		if c.MaxBodySize < 1 {
+			errs = append(errs, errors.New("maxbodysize must be at least 1"))
+		}
+		errs = append(errs, validation.Prefix("capture", c.Capture.Validate())...)
+	}
+	if _, err := compileProxyRoutes(c.Routes); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// RequiresRoles returns whether any of the routes requires a role, or viewing the captures of the enabled proxy does.
+// Route roles count even if the proxy is disabled, since they also apply to the typed endpoints.
+func (c ProxyConfig) RequiresRoles() bool {
+	return c.Enabled && c.Capture.Enabled && c.Capture.Role != "" || slices.ContainsFunc(c.Routes, func(route ProxyRoute) bool {
+		return route.Role != ""
+	})
+}
+
+func compileProxyRoutes(routes []ProxyRoute) ([]proxyRoute, error) {
+	result := make([]proxyRoute, 0, len(routes))
+	for i, route := range routes {
+		route.Method = strings.ToUpper(route.Method)
+		if !slices.Contains([]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, route.Method) {
+			return nil, fmt.Errorf("proxy route %d: unsupported method %q", i, route.Method)
+		}
+		for _, other := range result {
+			if other.Method == route.Method && other.Path == route.Path {
+				return nil, fmt.Errorf("proxy route %d: conflicts with another route for %s %s", i, route.Method, route.Path)
+			}
+		}
+		compiled := proxyRoute{ProxyRoute: route}
+		var err error
+		if _, err = regexp.Compile(route.Path); err != nil {
+			return nil, fmt.Errorf("proxy route %d: invalid path: %w", i, err)
+		}
+		// Patterns must match the whole path
+		compiled.compiledPath = regexp.MustCompile("^(?:" + route.Path + ")$")
+		if len(route.Schema) > 0 {
+			if compiled.schema, err = jsonschema.Compile(route.Schema); err != nil {
+				return nil, fmt.Errorf("proxy route %d: %w", i, err)
+			}
+		}
+		if route.RateLimit.Rate < 0 || route.RateLimit.Burst < 0 {
+			return nil, fmt.Errorf("proxy route %d: rate limit can't be negative", i)
+		}
+		if route.RateLimit.Rate > 0 {
+			if route.RateLimit.Burst == 0 {
+				route.RateLimit.Burst = int(math.Ceil(route.RateLimit.Rate))
+			}
+			compiled.limiter = middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+				Rate:      rate.Limit(route.RateLimit.Rate),
+				Burst:     route.RateLimit.Burst,
+				ExpiresIn: 3 * time.Minute,
+			})
+		}
+		result = append(result, compiled)
+	}
+	return result, nil
+}
+
+// authorize checks the role of the user, the rate limit for the client and the request body.
+func (r proxyRoute) authorize(roles []string, client string, body []byte) error {
+	if r.Role != "" && !slices.Contains(roles, r.Role) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Route %s %s requires role %s", r.Method, r.Path, r.Role))
+	}
+	if r.limiter != nil {
+		if allowed, _ := r.limiter.Allow(client); !allowed {
+			return echo.NewHTTPError(http.StatusTooManyRequests, fmt.Sprintf("Rate limit of route %s %s exceeded", r.Method, r.Path))
+		}
+	}
+	if r.schema == nil {
+		return nil
+	}
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid request body: %s", err))
+	}
+	if err := r.schema.Validate(document); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid request body: %s", err))
+	}
+	return nil
+}
+
+// readProxyBody reads the request body, so it can be validated and captured before it's proxied.
+// Bodies larger than maxSize are rejected.
+func readProxyBody(request *http.Request, maxSize int) ([]byte, error) {
+	tooLarge := echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body must not exceed %d bytes", maxSize))
+	if request.ContentLength > int64(maxSize) {
+		return nil, tooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(request.Body, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, tooLarge
+	}
+	request.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// captureWriter keeps the start of the response body, up to the given limit.
+type captureWriter struct {
+	http.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	if remaining := w.limit - w.body.Len(); remaining > 0 {
+		w.body.Write(data[:min(len(data), remaining)])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Unwrap allows http.ResponseController to flush and hijack the underlying writer.
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// proxyCaptured proxies the request, capturing the redacted request and response body.
+func proxyCaptured(c echo.Context, recorder *capture.Recorder, path string, requestBody []byte, proxyHandler echo.HandlerFunc) error {
+	response := c.Response()
+	writer := &captureWriter{ResponseWriter: response.Writer, limit: recorder.Config.MaxBodySize + 1}
+	response.Writer = writer
+	defer func() {
+		response.Writer = writer.ResponseWriter
+	}()
+	start := time.Now()
+	err := proxyHandler(c)
+	recorder.Add(capture.Record{
+		Time:         start,
+		Method:       c.Request().Method,
+		Path:         path,
+		Status:       response.Status,
+		Duration:     time.Since(start).Round(time.Millisecond).String(),
+		RequestBody:  recorder.Body(requestBody, c.Request().Header.Get(echo.HeaderContentEncoding)),
+		ResponseBody: recorder.Body(writer.body.Bytes(), response.Header().Get(echo.HeaderContentEncoding)),
+	})
+	return err
+}
+
+// errInvalidProxyPath is returned for proxy paths that can't be canonicalized safely.
+var errInvalidProxyPath = echo.NewHTTPError(http.StatusBadRequest, "Invalid proxy path")
+
+// canonicalProxyPath canonicalizes the (escaped) path of a proxy request on the Nuts node: empty segments (double slashes) are removed
+// and dot segments are resolved. It returns the decoded path, which is matched against the routes,
+// and the escaped path that is sent to the Nuts node, which decodes to the same path.
+// Paths are rejected if they contain control characters, dot segments that only appear after decoding
+// (e.g. in encoded or double-encoded slashes), or dot segments that would escape the root.
+func canonicalProxyPath(escapedPath string) (string, string, error) {
+	var decoded, escaped []string
+	for _, segment := range strings.Split(escapedPath, "/") {
+		if segment == "" {
+			continue
+		}
+		value, err := url.PathUnescape(segment)
+		if err != nil {
+			return "", "", errInvalidProxyPath
+		}
+		switch value {
+		case ".":
+			continue
+		case "..":
+			if len(decoded) == 0 {
+				return "", "", errInvalidProxyPath
+			}
+			decoded = decoded[:len(decoded)-1]
+			escaped = escaped[:len(escaped)-1]
+			continue
+		}
+		if !safeProxyPathSegment(value) {
+			return "", "", errInvalidProxyPath
+		}
+		// The Nuts node or something in between might decode the path once more
+		if doubleDecoded, err := url.PathUnescape(value); err == nil && !safeProxyPathSegment(doubleDecoded) {
+			return "", "", errInvalidProxyPath
+		}
+		decoded = append(decoded, value)
+		escaped = append(escaped, segment)
+	}
+	return "/" + strings.Join(decoded, "/"), "/" + strings.Join(escaped, "/"), nil
+}
+
+// safeProxyPathSegment returns whether the decoded segment contains no control characters,
+// and no dot segments when split on (decoded) slashes and backslashes.
+func safeProxyPathSegment(segment string) bool {
+	for _, r := range segment {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	for _, part := range strings.FieldsFunc(segment, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == "." || part == ".." {
+			return false
+		}
+	}
+	return segment != "." && segment != ".."
+}
+
+// Proxy passes requests to allowed endpoints through to the Nuts node.
+// Its routes and maximum body size can be replaced while running, see Reload.
+type Proxy struct {
+	settings atomic.Pointer[proxySettings]
+}
+
+// proxySettings are the compiled reloadable settings of the proxy.
+type proxySettings struct {
+	routes      []proxyRoute
+	maxBodySize int
+}
+
+// match returns the routes that allow the method and (canonical) path. All of them apply to the request.
+func (s *proxySettings) match(method string, path string) []proxyRoute {
+	var result []proxyRoute
+	for _, route := range s.routes {
+		if route.Method == method && route.compiledPath.MatchString(path) {
+			result = append(result, route)
+		}
+	}
+	return result
+}
+
+// Reload compiles the routes and maximum body size of the given config and makes the proxy use them.
+// The current settings are kept if the config is invalid.
+func (p *Proxy) Reload(config ProxyConfig) error {
+	routes, err := compileProxyRoutes(config.Routes)
+	if err != nil {
+		return err
+	}
+	p.settings.Store(&proxySettings{routes: routes, maxBodySize: config.MaxBodySize})
+	return nil
+}
+
+// NewProxy compiles the routes of the given config. If the proxy is disabled, the routes only apply to the calls the typed endpoints make (see NodeClient).
+func NewProxy(config ProxyConfig) (*Proxy, error) {
+	result := &Proxy{}
+	if err := result.Reload(config); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // ConfigureProxy configures the proxy middleware for the given Nuts node address.
-// It allows the web application to call a curated list of endpoints on the Nuts node.
-func ConfigureProxy(logger zerolog.Logger, e *echo.Echo, nodeAddress *url.URL) {
-	e.Use(middleware.ProxyWithConfig(middleware.ProxyConfig{
+// It allows the web application to call the endpoints allowed by the routes of the proxy on the Nuts node.
+// Request paths are canonicalized before they're matched against the (anchored) route patterns,
+// and the canonical path is what's sent to the Nuts node. If several routes match a request, all of them apply:
+// the user needs the role of each route, and the schema and rate limit of each route are checked.
+// Proxied responses carry a Deprecation header, since clients should use the typed endpoints instead.
+// If recorder isn't nil, proxied requests and responses are captured.
+func ConfigureProxy(logger zerolog.Logger, e *echo.Echo, nodeAddress *url.URL, result *Proxy, recorder *capture.Recorder) {
+	proxy := middleware.ProxyWithConfig(middleware.ProxyConfig{
 		Balancer: middleware.NewRoundRobinBalancer([]*middleware.ProxyTarget{
 			{
 				URL: nodeAddress,
 			},
 		}),
-		Rewrite: map[string]string{
-			"^" + proxyPath + "*": "/$1",
-		},
-		Skipper: func(c echo.Context) bool {
-			proxyURL := c.Request().URL.Path
-			if !strings.HasPrefix(proxyURL, proxyPath) {
+	})
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		proxyHandler := proxy(next)
+		return func(c echo.Context) error {
+			request := c.Request()
+			if !strings.HasPrefix(request.URL.Path, proxyPath) {
 				// Not a proxy request
-				return true
+				return next(c)
+			}
+			escapedPath := request.URL.EscapedPath()
+			if !strings.HasPrefix(escapedPath, proxyPath) {
+				return errInvalidProxyPath
+			}
+			proxyURL, upstreamPath, err := canonicalProxyPath(strings.TrimPrefix(escapedPath, proxyPath))
+			if err != nil {
+				return err
 			}
-			proxyURL = strings.TrimPrefix(proxyURL, proxyPath)
-			proxyURL = "/" + strings.TrimLeft(proxyURL, "/")
-			for _, route := range allowedProxyRoutes {
-				if c.Request().Method == route.method && route.compiledPath.MatchString(proxyURL) {
-					logger.Info().Msgf("proxying %s %s", c.Request().Method, proxyURL)
-					return false
+			settings := result.settings.Load()
+			routes := settings.match(request.Method, proxyURL)
+			if len(routes) == 0 {
+				return echo.NewHTTPError(http.StatusForbidden, "Proxy route not allowed")
+			}
+			body, err := readProxyBody(request, settings.maxBodySize)
+			if err != nil {
+				return err
+			}
+			for _, route := range routes {
+				if err := route.authorize(oidc.Roles(c), c.RealIP(), body); err != nil {
+					return err
 				}
 			}
-			_ = c.String(http.StatusForbidden, "Proxy route not allowed")
-			return true
-		},
-	}))
+			request.URL.Path = proxyURL
+			request.URL.RawPath = upstreamPath
+			// Make sure the Nuts node receives the path that was checked
+			if sentPath, err := url.PathUnescape(request.URL.EscapedPath()); err != nil || sentPath != proxyURL {
+				return errInvalidProxyPath
+			}
+			logger.Info().Msgf("proxying %s %s", request.Method, proxyURL)
+			c.Response().Header().Set("Deprecation", "true")
+			if recorder != nil {
+				return proxyCaptured(c, recorder, proxyURL, body, proxyHandler)
+			}
+			return proxyHandler(c)
+		}
+	})
 }
@@ -0,0 +1,349 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nuts-foundation/nuts-admin/capture"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyConfig_Validate(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		assert.NoError(t, DefaultProxyConfig().Validate())
+	})
+	testCases := map[string]struct {
+		routes []ProxyRoute
+		err    string
+	}{
+		"invalid path": {
+			routes: []ProxyRoute{{Method: "GET", Path: "/internal/(.*"}},
+			err:    "proxy route 0: invalid path: error parsing regexp: missing closing ): `/internal/(.*`",
+		},
+		"unsupported method": {
+			routes: []ProxyRoute{{Method: "TRACE", Path: "/internal"}},
+			err:    `proxy route 0: unsupported method "TRACE"`,
+		},
+		"conflicting routes": {
+			routes: []ProxyRoute{{Method: "get", Path: "/internal"}, {Method: "GET", Path: "/internal", Role: "admin"}},
+			err:    "proxy route 1: conflicts with another route for GET /internal",
+		},
+		"invalid schema": {
+			routes: []ProxyRoute{{Method: "POST", Path: "/internal", Schema: map[string]interface{}{"type": "text"}}},
+			err:    `proxy route 0: invalid schema: $.type: unknown type "text"`,
+		},
+		"negative rate limit": {
+			routes: []ProxyRoute{{Method: "POST", Path: "/internal", RateLimit: RateLimit{Rate: -1}}},
+			err:    "proxy route 0: rate limit can't be negative",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultProxyConfig()
+			config.Routes = testCase.routes
+
+			err := config.Validate()
+
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+	t.Run("invalid max body size", func(t *testing.T) {
+		config := DefaultProxyConfig()
+		config.MaxBodySize = 0
+
+		assert.EqualError(t, config.Validate(), "maxbodysize must be at least 1")
+	})
+	t.Run("invalid capture config", func(t *testing.T) {
+		config := DefaultProxyConfig()
+		config.Capture.Enabled = true
+		config.Capture.Size = 0
+
+		assert.EqualError(t, config.Validate(), "capture: size must be at least 1")
+	})
+	t.Run("capture requires role", func(t *testing.T) {
+		config := DefaultProxyConfig()
+		config.Capture.Enabled = true
+		config.Capture.Role = "admin"
+
+		assert.True(t, config.RequiresRoles())
+	})
+	t.Run("disabled", func(t *testing.T) {
+		config := ProxyConfig{Routes: []ProxyRoute{{Method: "GET", Path: "/internal/(.*", Role: "admin"}}}
+
+		assert.ErrorContains(t, config.Validate(), "proxy route 0: invalid path")
+		assert.True(t, config.RequiresRoles())
+	})
+}
+
+func TestDefaultProxyConfig(t *testing.T) {
+	proxy, err := NewProxy(DefaultProxyConfig())
+	require.NoError(t, err)
+	testCases := []struct {
+		method  string
+		path    string
+		allowed bool
+	}{
+		{http.MethodDelete, "/internal/vcr/v2/issuer/vc/did:web:example.com%231", true},
+		{http.MethodDelete, "/internal/vcr/v2/issuer/vc/a/b/c", false},
+		{http.MethodDelete, "/internal/vcr/v2/issuer/vc/a%2Fb", false},
+		{http.MethodDelete, "/internal/vcr/v2/holder/hospital_x/vc/urn:uuid:1", true},
+		{http.MethodDelete, "/internal/vcr/v2/holder/hospital_x/vc/1/other", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.method+" "+testCase.path, func(t *testing.T) {
+			path, _, err := canonicalProxyPath(testCase.path)
+			require.NoError(t, err)
+
+			routes := proxy.settings.Load().match(testCase.method, path)
+
+			assert.Equal(t, testCase.allowed, len(routes) > 0)
+		})
+	}
+}
+
+func TestConfigureProxy(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(request.Method + " " + request.URL.EscapedPath()))
+	}))
+	defer node.Close()
+	nodeURL, _ := url.Parse(node.URL)
+	e := echo.New()
+	routes, err := NewProxy(ProxyConfig{MaxBodySize: 64, Routes: []ProxyRoute{
+		{Method: http.MethodGet, Path: "/internal/discovery/v1"},
+		{Method: http.MethodPost, Path: "/internal/vcr/v2/issuer/vc", Schema: map[string]interface{}{"type": "object", "required": []interface{}{"type"}}},
+		{Method: http.MethodGet, Path: "/internal/vcr/v2/issuer/vc/search", RateLimit: RateLimit{Rate: 0.001, Burst: 1}},
+		{Method: http.MethodDelete, Path: "/internal/vcr/v2/issuer/vc/(.*)", Role: "issuer"},
+		{Method: http.MethodGet, Path: "/internal/vcr/v2/holder/([^/]+)/vc"},
+		{Method: http.MethodGet, Path: "/internal/discovery/v1/[a-z]+"},
+		{Method: http.MethodGet, Path: "/internal/discovery/v1/(.*)", Role: "admin"},
+	}})
+	require.NoError(t, err)
+	ConfigureProxy(zerolog.Nop(), e, nodeURL, routes, nil)
+	proxy := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		response := proxy(http.MethodGet, "/api/proxy/internal/discovery/v1", "")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "GET /internal/discovery/v1", response.Body.String())
+		assert.Equal(t, "true", response.Header().Get("Deprecation"))
+	})
+	t.Run("not allowed", func(t *testing.T) {
+		response := proxy(http.MethodPost, "/api/proxy/internal/discovery/v1", "")
+
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+	t.Run("request body matches schema", func(t *testing.T) {
+		response := proxy(http.MethodPost, "/api/proxy/internal/vcr/v2/issuer/vc", `{"type": "NutsOrganizationCredential"}`)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("request body doesn't match schema", func(t *testing.T) {
+		response := proxy(http.MethodPost, "/api/proxy/internal/vcr/v2/issuer/vc", `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), "$.type: is required")
+	})
+	t.Run("request body too large", func(t *testing.T) {
+		response := proxy(http.MethodPost, "/api/proxy/internal/vcr/v2/issuer/vc", `{"type": "`+strings.Repeat("x", 64)+`"}`)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+	})
+	t.Run("rate limited", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, proxy(http.MethodGet, "/api/proxy/internal/vcr/v2/issuer/vc/search", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, proxy(http.MethodGet, "/api/proxy/internal/vcr/v2/issuer/vc/search", "").Code)
+	})
+	t.Run("missing role", func(t *testing.T) {
+		response := proxy(http.MethodDelete, "/api/proxy/internal/vcr/v2/issuer/vc/1", "")
+
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		assert.Contains(t, response.Body.String(), "requires role issuer")
+	})
+	t.Run("all matching routes apply", func(t *testing.T) {
+		response := proxy(http.MethodGet, "/api/proxy/internal/discovery/v1/service", "")
+
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		assert.Contains(t, response.Body.String(), "requires role admin")
+	})
+	t.Run("path is canonicalized", func(t *testing.T) {
+		response := proxy(http.MethodGet, "/api/proxy//internal/./discovery//v1/", "")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "GET /internal/discovery/v1", response.Body.String())
+	})
+	t.Run("escaped path segments are forwarded as-is", func(t *testing.T) {
+		response := proxy(http.MethodGet, "/api/proxy/internal/vcr/v2/holder/did:web:example.com%3A8080/vc", "")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "GET /internal/vcr/v2/holder/did:web:example.com%3A8080/vc", response.Body.String())
+	})
+	t.Run("patterns are anchored", func(t *testing.T) {
+		for _, path := range []string{
+			"/api/proxy/x/internal/discovery/v1",
+			"/api/proxy/internal/discovery/v1/extra",
+			"/api/proxy/internal/discovery/v10",
+		} {
+			assert.Equal(t, http.StatusForbidden, proxy(http.MethodGet, path, "").Code, path)
+		}
+	})
+	t.Run("dot segments are resolved before matching", func(t *testing.T) {
+		response := proxy(http.MethodGet, "/api/proxy/internal/vcr/v2/holder/x/vc/../../../../../status", "")
+
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+	t.Run("encoded traversal is rejected", func(t *testing.T) {
+		for _, path := range []string{
+			"/api/proxy/internal/vcr/v2/holder/..%2F..%2F..%2Fstatus/vc",
+			"/api/proxy/internal/vcr/v2/holder/%252e%252e%252fstatus/vc",
+			"/api/proxy/internal/vcr/v2/holder/..%5Cstatus/vc",
+			"/api/proxy/internal/vcr/v2/holder/%00/vc",
+			"/api/proxy/../../internal/discovery/v1",
+		} {
+			assert.Equal(t, http.StatusBadRequest, proxy(http.MethodGet, path, "").Code, path)
+		}
+	})
+}
+
+func TestProxy_Reload(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer node.Close()
+	nodeURL, _ := url.Parse(node.URL)
+	e := echo.New()
+	proxy, err := NewProxy(ProxyConfig{MaxBodySize: 64, Routes: []ProxyRoute{
+		{Method: http.MethodGet, Path: "/internal/discovery/v1"},
+	}})
+	require.NoError(t, err)
+	ConfigureProxy(zerolog.Nop(), e, nodeURL, proxy, nil)
+	call := func(method string, path string) int {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder.Code
+	}
+
+	t.Run("routes are replaced", func(t *testing.T) {
+		err := proxy.Reload(ProxyConfig{MaxBodySize: 64, Routes: []ProxyRoute{
+			{Method: http.MethodGet, Path: "/internal/vcr/v2/issuer/vc/search"},
+		}})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/api/proxy/internal/discovery/v1"))
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/proxy/internal/vcr/v2/issuer/vc/search"))
+	})
+	t.Run("invalid routes keep the current routes", func(t *testing.T) {
+		err := proxy.Reload(ProxyConfig{MaxBodySize: 64, Routes: []ProxyRoute{
+			{Method: http.MethodGet, Path: "("},
+		}})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/proxy/internal/vcr/v2/issuer/vc/search"))
+	})
+}
+
+func TestConfigureProxy_capture(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusBadRequest)
+		_, _ = writer.Write([]byte(`{"title":"invalid credential","credentialSubject":{"name":"Hospital X"}}`))
+	}))
+	defer node.Close()
+	nodeURL, _ := url.Parse(node.URL)
+	e := echo.New()
+	captureConfig := capture.DefaultConfig()
+	captureConfig.Enabled = true
+	recorder := capture.NewRecorder(captureConfig)
+	proxy, err := NewProxy(ProxyConfig{MaxBodySize: 1024, Routes: []ProxyRoute{
+		{Method: http.MethodPost, Path: "/internal/vcr/v2/issuer/vc"},
+	}})
+	require.NoError(t, err)
+	ConfigureProxy(zerolog.Nop(), e, nodeURL, proxy, recorder)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/proxy/internal/vcr/v2/issuer/vc", strings.NewReader(`{"type":"NutsOrganizationCredential","credentialSubject":{"name":"Hospital X"}}`))
+	response := httptest.NewRecorder()
+	e.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "Hospital X", "the client receives the unredacted response")
+	records := recorder.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, http.MethodPost, records[0].Method)
+	assert.Equal(t, "/internal/vcr/v2/issuer/vc", records[0].Path)
+	assert.Equal(t, http.StatusBadRequest, records[0].Status)
+	assert.JSONEq(t, `{"type":"NutsOrganizationCredential","credentialSubject":"[redacted]"}`, string(records[0].RequestBody))
+	assert.JSONEq(t, `{"title":"invalid credential","credentialSubject":"[redacted]"}`, string(records[0].ResponseBody))
+}
+
+func TestCanonicalProxyPath(t *testing.T) {
+	testCases := []struct {
+		path    string
+		checked string
+		forward string
+		invalid bool
+	}{
+		{path: "internal/discovery/v1", checked: "/internal/discovery/v1", forward: "/internal/discovery/v1"},
+		{path: "//internal//discovery/v1/", checked: "/internal/discovery/v1", forward: "/internal/discovery/v1"},
+		{path: "internal/./discovery/v1", checked: "/internal/discovery/v1", forward: "/internal/discovery/v1"},
+		{path: "internal/discovery/v1/../../status", checked: "/internal/status", forward: "/internal/status"},
+		{path: "internal/%2e%2e/status", checked: "/status", forward: "/status"},
+		{path: "internal/did:web:example.com%3A8080", checked: "/internal/did:web:example.com:8080", forward: "/internal/did:web:example.com%3A8080"},
+		{path: "", checked: "/", forward: "/"},
+		{path: "..", invalid: true},
+		{path: "internal/../..", invalid: true},
+		{path: "internal/..%2F..%2Fstatus", invalid: true},
+		{path: "internal/%2E%2E%5Cstatus", invalid: true},
+		{path: "internal/%252e%252e%252fstatus", invalid: true},
+		{path: "internal/%252e%252e", invalid: true},
+		{path: "internal/%00", invalid: true},
+		{path: "internal/%0a", invalid: true},
+		{path: "internal/%zz", invalid: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			checked, forward, err := canonicalProxyPath(testCase.path)
+
+			if testCase.invalid {
+				assert.Equal(t, errInvalidProxyPath, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.checked, checked)
+			assert.Equal(t, testCase.forward, forward)
+		})
+	}
+}
+
+func FuzzCanonicalProxyPath(f *testing.F) {
+	for _, seed := range []string{"internal/discovery/v1", "//a/./b/../c/", "a/%2e%2e/b", "a/..%2Fb", "a/%252e%252e%252fb", "%00", "a/%zz"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, path string) {
+		checked, forward, err := canonicalProxyPath(path)
+		if err != nil {
+			return
+		}
+		// Encoded slashes may be part of a segment (e.g. a credential ID), so the invariants apply to the raw segments
+		assert.True(t, strings.HasPrefix(forward, "/"))
+		assert.NotContains(t, forward, "//")
+		for _, segment := range strings.Split(forward, "/") {
+			value, err := url.PathUnescape(segment)
+			require.NoError(t, err)
+			assert.NotEqual(t, ".", value)
+			assert.NotEqual(t, "..", value)
+		}
+		unescaped, err := url.PathUnescape(forward)
+		require.NoError(t, err)
+		assert.Equal(t, checked, unescaped)
+	})
+}
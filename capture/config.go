@@ -0,0 +1,50 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Config struct {
+	// Enabled controls whether proxied requests and responses are captured.
+	Enabled bool `koanf:"enabled"`
+	// Size is the number of captures that are kept. When full, the oldest capture is dropped.
+	Size int `koanf:"size"`
+	// MaxBodySize is the maximum size in bytes of a captured body. Larger bodies aren't captured, since they can't be redacted.
+	MaxBodySize int `koanf:"maxbodysize"`
+	// Redact contains the fields that are masked in captured JSON bodies, as dot-separated property names (* matches any property).
+	// A rule matches the field at any depth, e.g. credentialSubject matches the credential subject of credentials in search results as well.
+	Redact []string `koanf:"redact"`
+	// Role is the role the user must have to view the captures (see oidc.rolesclaim). If empty, any authenticated user may view them.
+	Role string `koanf:"role"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enabled:     false,
+		Size:        100,
+		MaxBodySize: 64 * 1024,
+		Redact:      []string{"credentialSubject"},
+	}
+}
+
+// Validate checks the config if capturing is enabled. The returned error joins all problems (see errors.Join).
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	if c.Size < 1 {
+		errs = append(errs, errors.New("size must be at least 1"))
+	}
+	if c.MaxBodySize < 1 {
+		errs = append(errs, errors.New("maxbodysize must be at least 1"))
+	}
+	for _, rule := range c.Redact {
+		if rule == "" || strings.Contains(rule, "..") || strings.HasPrefix(rule, ".") || strings.HasSuffix(rule, ".") {
+			errs = append(errs, fmt.Errorf("invalid redaction rule %q", rule))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+// Package capture records proxied requests and responses for debugging, with personal data redacted.
+package capture
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Record is a captured request to the Nuts node and its response.
+type Record struct {
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Status   int       `json:"status"`
+	Duration string    `json:"duration"`
+	// RequestBody and ResponseBody are the redacted bodies. A body that isn't JSON is captured as JSON string.
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+}
+
+// Recorder keeps the most recent captures in a ring buffer.
+type Recorder struct {
+	Config   Config
+	redactor redactor
+	mux      sync.Mutex
+	records  []Record
+	next     int
+}
+
+func NewRecorder(config Config) *Recorder {
+	return &Recorder{
+		Config:   config,
+		redactor: newRedactor(config.Redact),
+		records:  make([]Record, 0, config.Size),
+	}
+}
+
+// Body returns the redacted form of a request or response body, to be stored in a Record.
+// Only the first Config.MaxBodySize+1 bytes of the body are needed.
+func (r *Recorder) Body(data []byte, contentEncoding string) json.RawMessage {
+	return r.redactor.body(data, contentEncoding, r.Config.MaxBodySize)
+}
+
+// Add stores the record, dropping the oldest record if the buffer is full.
+func (r *Recorder) Add(record Record) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if len(r.records) < r.Config.Size {
+		r.records = append(r.records, record)
+		return
+	}
+	r.records[r.next] = record
+	r.next = (r.next + 1) % r.Config.Size
+}
+
+// Records returns the captured records, most recent first.
+func (r *Recorder) Records() []Record {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	result := make([]Record, 0, len(r.records))
+	for i := len(r.records) - 1; i >= 0; i-- {
+		result = append(result, r.records[(r.next+i)%len(r.records)])
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package capture
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	paths := func(records []Record) []string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.Path)
+		}
+		return result
+	}
+	t.Run("empty", func(t *testing.T) {
+		recorder := NewRecorder(Config{Size: 2})
+
+		assert.Empty(t, recorder.Records())
+	})
+	t.Run("not full", func(t *testing.T) {
+		recorder := NewRecorder(Config{Size: 3})
+		recorder.Add(Record{Path: "/1"})
+		recorder.Add(Record{Path: "/2"})
+
+		assert.Equal(t, []string{"/2", "/1"}, paths(recorder.Records()))
+	})
+	t.Run("oldest records are dropped", func(t *testing.T) {
+		recorder := NewRecorder(Config{Size: 3})
+		for _, path := range []string{"/1", "/2", "/3", "/4", "/5"} {
+			recorder.Add(Record{Path: path})
+		}
+
+		assert.Equal(t, []string{"/5", "/4", "/3"}, paths(recorder.Records()))
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Enabled = true
+
+		assert.NoError(t, config.Validate())
+	})
+	t.Run("disabled", func(t *testing.T) {
+		assert.NoError(t, Config{}.Validate())
+	})
+	t.Run("invalid size", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Enabled = true
+		config.Size = 0
+
+		assert.EqualError(t, config.Validate(), "size must be at least 1")
+	})
+	t.Run("invalid rule", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Enabled = true
+		config.Redact = []string{"credentialSubject..name"}
+
+		assert.EqualError(t, config.Validate(), `invalid redaction rule "credentialSubject..name"`)
+	})
+}
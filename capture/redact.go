@@ -0,0 +1,100 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the values of redacted fields.
+const Redacted = "[redacted]"
+
+// jwtPattern matches compact JWS (e.g. JWT credentials and presentations), which are redacted regardless of the rules
+// since their payload can't be redacted field by field.
+var jwtPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+
+// redactor masks fields in JSON documents.
+type redactor struct {
+	rules [][]string
+}
+
+func newRedactor(rules []string) redactor {
+	result := redactor{}
+	for _, rule := range rules {
+		result.rules = append(result.rules, strings.Split(rule, "."))
+	}
+	return result
+}
+
+// body returns the captured form of a body: redacted JSON, or the body as JSON string if it isn't JSON.
+// Bodies that exceed the maximum size or are encoded (e.g. gzip) aren't captured, since they can't be redacted.
+// The data may be cut off after maxSize+1 bytes.
+func (r redactor) body(data []byte, contentEncoding string, maxSize int) json.RawMessage {
+	var result interface{}
+	switch {
+	case len(data) == 0:
+		return nil
+	case contentEncoding != "" && contentEncoding != "identity":
+		result = fmt.Sprintf("[not captured: %s encoded]", contentEncoding)
+	case len(data) > maxSize:
+		result = fmt.Sprintf("[not captured: exceeds the maximum of %d bytes]", maxSize)
+	default:
+		var document interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&document); err != nil || decoder.More() {
+			result = string(data)
+		} else {
+			result = r.redact(document, nil)
+		}
+	}
+	encoded, _ := json.Marshal(result)
+	return encoded
+}
+
+// redact returns the value with the fields that match a rule masked. Arrays don't add to the path of their items.
+func (r redactor) redact(value interface{}, path []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, property := range v {
+			propertyPath := append(path[:len(path):len(path)], name)
+			if r.matches(propertyPath) {
+				v[name] = Redacted
+			} else {
+				v[name] = r.redact(property, propertyPath)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redact(item, path)
+		}
+	case string:
+		if jwtPattern.MatchString(v) {
+			return Redacted
+		}
+	}
+	return value
+}
+
+// matches returns whether the path ends with the properties of any rule.
+func (r redactor) matches(path []string) bool {
+	for _, rule := range r.rules {
+		if len(rule) > len(path) {
+			continue
+		}
+		suffix := path[len(path)-len(rule):]
+		matches := true
+		for i, name := range rule {
+			if name != "*" && name != suffix[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// command is a (sub)command of the nuts-admin command line.
+// Commands either run something or group subcommands.
+type command struct {
+	name string
+	// args describes the positional arguments in the usage.
+	args  string
+	short string
+	// minArgs and maxArgs limit the number of positional arguments, a negative maxArgs means no limit.
+	minArgs     int
+	maxArgs     int
+	flags       func(flags *pflag.FlagSet)
+	run         func(out io.Writer, flags *pflag.FlagSet, args []string) int
+	subcommands []*command
+}
+
+// rootCommand returns the command tree of nuts-admin.
+func rootCommand() *command {
+	return &command{
+		name: "nuts-admin",
+		subcommands: []*command{
+			serveCommand(),
+			{
+				name:  "config",
+				short: "Inspect the configuration",
+				subcommands: []*command{
+					{
+						name:  "print",
+						short: "Print the effective configuration, with secrets masked",
+						run:   runConfigPrint,
+					},
+					{
+						name:  "check",
+						short: "Print and validate the effective configuration",
+						run: func(out io.Writer, flags *pflag.FlagSet, _ []string) int {
+							return runConfigCheck(out, resolveConfigFile(flags))
+						},
+					},
+				},
+			},
+			identityCommand(),
+			credentialCommand(),
+			discoveryCommand(),
+			{
+				name:    "lint",
+				args:    "<file or directory>...",
+				short:   "Lint discovery service definitions and policy files",
+				maxArgs: -1,
+				run: func(out io.Writer, _ *pflag.FlagSet, args []string) int {
+					return runLint(out, args)
+				},
+			},
+		},
+	}
+}
+
+// execute runs the command selected by the arguments, and returns the exit code.
+// Without a command, the server is started, so flags can be passed to the server as before there were commands.
+func execute(out io.Writer, root *command, args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		args = append([]string{"serve"}, args...)
+	}
+	if args[0] == "help" {
+		root.printUsage(out, root.name)
+		return 0
+	}
+	cmd := root
+	path := root.name
+	for len(args) > 0 && len(cmd.subcommands) > 0 {
+		sub := cmd.subcommand(args[0])
+		if sub == nil {
+			if !strings.HasPrefix(args[0], "-") {
+				_, _ = fmt.Fprintf(out, "unknown command: %s %s\n\n", path, args[0])
+			}
+			break
+		}
+		cmd = sub
+		path += " " + sub.name
+		args = args[1:]
+	}
+	if cmd.run == nil {
+		cmd.printUsage(out, path)
+		return 2
+	}
+	flags := pflag.NewFlagSet(path, pflag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.String(configFileFlag, defaultConfigFile, "Application config file")
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	flags.Usage = func() {
+		cmd.printUsage(out, path)
+		_, _ = fmt.Fprintf(out, "\nFlags:\n%s", flags.FlagUsages())
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return 0
+		}
+		_, _ = fmt.Fprintf(out, "%s\n\n", err)
+		flags.Usage()
+		return 2
+	}
+	if flags.NArg() < cmd.minArgs || (cmd.maxArgs >= 0 && flags.NArg() > cmd.maxArgs) {
+		flags.Usage()
+		return 2
+	}
+	return cmd.run(out, flags, flags.Args())
+}
+
+func (c *command) subcommand(name string) *command {
+	for _, sub := range c.subcommands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+func (c *command) printUsage(out io.Writer, path string) {
+	if len(c.subcommands) == 0 {
+		_, _ = fmt.Fprintf(out, "usage: %s\n", strings.TrimSpace(path+" [flags] "+c.args))
+		if c.short != "" {
+			_, _ = fmt.Fprintf(out, "\n%s\n", c.short)
+		}
+		return
+	}
+	_, _ = fmt.Fprintf(out, "usage: %s <command> [flags]\n\nCommands:\n", path)
+	for _, sub := range c.subcommands {
+		_, _ = fmt.Fprintf(out, "  %-10s %s\n", sub.name, sub.short)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute(t *testing.T) {
+	var ran []string
+	root := &command{
+		name: "nuts-admin",
+		subcommands: []*command{
+			{
+				name: "serve",
+				flags: func(flags *pflag.FlagSet) {
+					flags.Bool("live", false, "")
+				},
+				run: func(_ io.Writer, flags *pflag.FlagSet, _ []string) int {
+					live, _ := flags.GetBool("live")
+					configFile, _ := flags.GetString(configFileFlag)
+					ran = []string{"serve", configFile}
+					if live {
+						ran = append(ran, "live")
+					}
+					return 0
+				},
+			},
+			{
+				name: "identity",
+				subcommands: []*command{
+					{
+						name:    "show",
+						args:    "<subject>",
+						minArgs: 1,
+						maxArgs: 1,
+						run: func(_ io.Writer, _ *pflag.FlagSet, args []string) int {
+							ran = append([]string{"identity show"}, args...)
+							return 0
+						},
+					},
+				},
+			},
+		},
+	}
+	run := func(args ...string) (int, string) {
+		ran = nil
+		out := new(bytes.Buffer)
+		return execute(out, root, args), out.String()
+	}
+
+	t.Run("subcommand with arguments", func(t *testing.T) {
+		exitCode, _ := run("identity", "show", "hospital")
+
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, []string{"identity show", "hospital"}, ran)
+	})
+	t.Run("serve by default", func(t *testing.T) {
+		exitCode, _ := run()
+
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, []string{"serve", defaultConfigFile}, ran)
+	})
+	t.Run("flags without a command are passed to serve", func(t *testing.T) {
+		exitCode, _ := run("--configfile", "other.yaml")
+
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, []string{"serve", "other.yaml"}, ran)
+	})
+	t.Run("serve with flags", func(t *testing.T) {
+		exitCode, _ := run("serve", "--live")
+
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, []string{"serve", defaultConfigFile, "live"}, ran)
+	})
+	t.Run("missing argument", func(t *testing.T) {
+		exitCode, out := run("identity", "show")
+
+		assert.Equal(t, 2, exitCode)
+		assert.Nil(t, ran)
+		assert.True(t, strings.HasPrefix(out, "usage: nuts-admin identity show [flags] <subject>"))
+	})
+	t.Run("unknown command", func(t *testing.T) {
+		exitCode, out := run("identity", "delete")
+
+		assert.Equal(t, 2, exitCode)
+		assert.Contains(t, out, "unknown command: nuts-admin identity delete")
+		assert.Contains(t, out, "  show")
+	})
+	t.Run("unknown flag", func(t *testing.T) {
+		exitCode, out := run("serve", "--unknown")
+
+		assert.Equal(t, 2, exitCode)
+		assert.Contains(t, out, "unknown flag: --unknown")
+	})
+	t.Run("help", func(t *testing.T) {
+		exitCode, out := run("help")
+
+		assert.Equal(t, 0, exitCode)
+		assert.Contains(t, out, "  identity")
+	})
+}
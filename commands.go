@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/nuts-foundation/nuts-admin/discovery"
+	"github.com/nuts-foundation/nuts-admin/issuer"
+	"github.com/spf13/pflag"
+)
+
+// serviceFunc performs a command using the services that call the Nuts node. The result is printed as JSON.
+type serviceFunc func(ctx context.Context, services nodeServices, flags *pflag.FlagSet, args []string) (interface{}, error)
+
+// withServices loads and validates the config, and runs the given function with the services that call the Nuts node.
+// It returns the exit code: 0 if the function succeeded, 1 if it failed and 2 if the config can't be loaded or is invalid.
+func withServices(f serviceFunc) func(out io.Writer, flags *pflag.FlagSet, args []string) int {
+	return func(out io.Writer, flags *pflag.FlagSet, args []string) int {
+		configFilePath := resolveConfigFile(flags)
+		config, err := readConfig(configFilePath)
+		if err == nil {
+			err = config.Validate()
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(out, "invalid config %s: %s\n", configFilePath, err)
+			return 2
+		}
+		result, err := f(context.Background(), newNodeServices(config, http.DefaultClient), flags, args)
+		if err != nil {
+			_, _ = fmt.Fprintf(out, "error: %s\n", err)
+			return 1
+		}
+		if result != nil {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			_, _ = fmt.Fprintf(out, "%s\n", data)
+		}
+		return 0
+	}
+}
+
+func identityCommand() *command {
+	return &command{
+		name:  "identity",
+		short: "Manage identities (subjects) on the Nuts node",
+		subcommands: []*command{
+			{
+				name:  "list",
+				short: "List the identities",
+				run: withServices(func(ctx context.Context, services nodeServices, _ *pflag.FlagSet, _ []string) (interface{}, error) {
+					return services.identity.List(ctx)
+				}),
+			},
+			{
+				name:    "create",
+				args:    "[subject]",
+				short:   "Create an identity, the Nuts node generates the subject if it's not given",
+				maxArgs: 1,
+				run: withServices(func(ctx context.Context, services nodeServices, _ *pflag.FlagSet, args []string) (interface{}, error) {
+					var subject *string
+					if len(args) > 0 {
+						subject = &args[0]
+					}
+					return services.identity.Create(ctx, subject)
+				}),
+			},
+			{
+				name:    "show",
+				args:    "<subject>",
+				short:   "Show the DID documents, discovery services and wallet credentials of an identity",
+				minArgs: 1,
+				maxArgs: 1,
+				run: withServices(func(ctx context.Context, services nodeServices, _ *pflag.FlagSet, args []string) (interface{}, error) {
+					return services.identity.Get(ctx, args[0])
+				}),
+			},
+		},
+	}
+}
+
+func credentialCommand() *command {
+	return &command{
+		name:  "credential",
+		short: "Manage issued credentials",
+		subcommands: []*command{
+			{
+				name:    "issue",
+				args:    "<request file, or - for stdin>",
+				short:   "Issue a credential, the request is the JSON body of POST /api/issuer/vc",
+				minArgs: 1,
+				maxArgs: 1,
+				run: withServices(func(ctx context.Context, services nodeServices, _ *pflag.FlagSet, args []string) (interface{}, error) {
+					var data []byte
+					var err error
+					if args[0] == "-" {
+						data, err = io.ReadAll(os.Stdin)
+					} else {
+						data, err = os.ReadFile(args[0])
+					}
+					if err != nil {
+						return nil, err
+					}
+					var request issuer.IssueRequest
+					if err := json.Unmarshal(data, &request); err != nil {
+						return nil, fmt.Errorf("invalid request: %w", err)
+					}
+					return services.issuer.IssueCredential(ctx, request)
+				}),
+			},
+			{
+				name:    "revoke",
+				args:    "<credential ID>",
+				short:   "Revoke an issued credential",
+				minArgs: 1,
+				maxArgs: 1,
+				run: withServices(func(ctx context.Context, services nodeServices, _ *pflag.FlagSet, args []string) (interface{}, error) {
+					return nil, services.issuer.Revoke(ctx, args[0])
+				}),
+			},
+			{
+				name:  "list",
+				short: "List the credentials issued by a DID, newest first",
+				flags: func(flags *pflag.FlagSet) {
+					flags.String("issuer", "", "DID of the issuer (required)")
+					flags.StringSlice("type", nil, "Credential type to list, can be repeated (required)")
+				},
+				run: withServices(func(ctx context.Context, services nodeServices, flags *pflag.FlagSet, _ []string) (interface{}, error) {
+					issuerDID, _ := flags.GetString("issuer")
+					credentialTypes, _ := flags.GetStringSlice("type")
+					if issuerDID == "" || len(credentialTypes) == 0 {
+						return nil, errors.New("--issuer and --type are required")
+					}
+					return services.issuer.GetIssuedCredentials(ctx, issuerDID, credentialTypes)
+				}),
+			},
+		},
+	}
+}
+
+func discoveryCommand() *command {
+	return &command{
+		name:  "discovery",
+		short: "Manage discovery service registrations",
+		subcommands: []*command{
+			{
+				name:    "activate",
+				args:    "<subject> <service ID>",
+				short:   "Activate a discovery service for an identity",
+				minArgs: 2,
+				maxArgs: 2,
+				flags: func(flags *pflag.FlagSet) {
+					flags.StringToString("parameter", nil, "Registration parameter as key=value, can be repeated")
+				},
+				run: withServices(func(ctx context.Context, services nodeServices, flags *pflag.FlagSet, args []string) (interface{}, error) {
+					parameters, _ := flags.GetStringToString("parameter")
+					registrationParameters := make(map[string]interface{}, len(parameters))
+					for key, value := range parameters {
+						registrationParameters[key] = value
+					}
+					if err := services.discovery.Activate(ctx, args[1], args[0], registrationParameters); err != nil {
+						return nil, err
+					}
+					return services.discovery.ActivationStatus(ctx, args[1], args[0])
+				}),
+			},
+			{
+				name:    "status",
+				args:    "<subject> [service ID]",
+				short:   "Show the activation status of the discovery services for an identity",
+				minArgs: 1,
+				maxArgs: 2,
+				run: withServices(func(ctx context.Context, services nodeServices, _ *pflag.FlagSet, args []string) (interface{}, error) {
+					if len(args) > 1 {
+						return services.discovery.ActivationStatus(ctx, args[1], args[0])
+					}
+					definitions, err := services.discovery.GetDiscoveryServices(ctx)
+					if err != nil {
+						return nil, err
+					}
+					result := make([]discovery.DIDStatus, 0, len(definitions))
+					for _, definition := range definitions {
+						status, err := services.discovery.ActivationStatus(ctx, definition.Id, args[0])
+						if err != nil {
+							return nil, fmt.Errorf("discovery service %s: %w", definition.Id, err)
+						}
+						result = append(result, *status)
+					}
+					return result, nil
+				}),
+			},
+		},
+	}
+}
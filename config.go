@@ -10,11 +10,20 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"reflect"
 	"strings"
 
+	"github.com/nuts-foundation/nuts-admin/api"
+	"github.com/nuts-foundation/nuts-admin/credentialprofile"
+	"github.com/nuts-foundation/nuts-admin/expiry"
 	"github.com/nuts-foundation/nuts-admin/model"
 	"github.com/nuts-foundation/nuts-admin/oidc"
+	"github.com/nuts-foundation/nuts-admin/policy"
+	"github.com/nuts-foundation/nuts-admin/registration"
+	"github.com/nuts-foundation/nuts-admin/validation"
 	"golang.org/x/crypto/ssh"
 
 	"github.com/knadh/koanf"
@@ -22,6 +31,8 @@ import (
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
 	"github.com/spf13/pflag"
 )
 
@@ -36,17 +47,36 @@ func defaultConfig() Config {
 		Node: Node{
 			Address: "http://localhost:8081",
 		},
-		AccessLogs: true,
-		OIDC:       oidc.DefaultConfig(),
+		AccessLogs:   true,
+		LogLevel:     "info",
+		DataDir:      "data",
+		OIDC:         oidc.DefaultConfig(),
+		Expiry:       expiry.DefaultConfig(),
+		Registration: registration.DefaultConfig(),
+		Proxy:        api.DefaultProxyConfig(),
+		ServiceProfiles: []model.ServiceProfile{
+			{Type: "fhir"},
+			{Type: "oauth"},
+		},
 	}
 }
 
 type Config struct {
-	HTTPPort           int                       `koanf:"port"`
-	BaseURL            string                    `koanf:"url"`
-	Node               Node                      `koanf:"node"`
-	AccessLogs         bool                      `koanf:"accesslogs"`
+	HTTPPort   int    `koanf:"port"`
+	BaseURL    string `koanf:"url"`
+	Node       Node   `koanf:"node"`
+	AccessLogs bool   `koanf:"accesslogs"`
+	LogLevel   string `koanf:"loglevel"`
+	// TrustedProxies are the IP ranges (CIDR notation) of the reverse proxies whose X-Forwarded-For header is used to determine the client IP address.
+	TrustedProxies []string `koanf:"trustedproxies"`
+	// DataDir is the directory where nuts-admin stores its own data, e.g. the state of background jobs.
+	DataDir            string                    `koanf:"datadir"`
 	CredentialProfiles []model.CredentialProfile `koanf:"credentialprofiles"`
+	ServiceProfiles    []model.ServiceProfile    `koanf:"serviceprofiles"`
+	Expiry             expiry.Config             `koanf:"expiry"`
+	Registration       registration.Config       `koanf:"registration"`
+	Policy             policy.Config             `koanf:"policy"`
+	Proxy              api.ProxyConfig           `koanf:"proxy"`
 	apiKey             crypto.Signer
 	OIDC               oidc.Config `koanf:"oidc"`
 }
@@ -62,7 +92,7 @@ type NodeAuth struct {
 	// User contains the API key user that will go into the iss field. It must match the user with the public key from the authorized_keys file in the Nuts node
 	User string `koanf:"user"`
 	// Audience dictates the aud field of the created JWT
-	Audience string `kaonf:"audience"`
+	Audience string `koanf:"audience"`
 }
 
 func generateSessionKey() (*ecdsa.PrivateKey, error) {
@@ -74,84 +104,271 @@ func generateSessionKey() (*ecdsa.PrivateKey, error) {
 	return key, nil
 }
 
+// Validate checks every section of the config. All problems are reported at once: the returned error joins them (see errors.Join).
 func (c Config) Validate() error {
-	if err := c.OIDC.Validate(); err != nil {
-		return fmt.Errorf("oidc config error: %w", err)
+	var errs []error
+	addErrors := func(section string, err error) {
+		errs = append(errs, validation.Prefix(section+" config error", err)...)
+	}
+
+	if c.HTTPPort < 1 || c.HTTPPort > 65535 {
+		errs = append(errs, fmt.Errorf("port must be between 1 and 65535: %d", c.HTTPPort))
+	}
+
+	if c.BaseURL != "" {
+		if err := validateHTTPURL(c.BaseURL); err != nil {
+			errs = append(errs, fmt.Errorf("url: %w", err))
+		}
+	} else if c.OIDC.Enabled {
+		errs = append(errs, errors.New("url is required when oidc is enabled"))
 	}
 
-	if c.OIDC.Enabled && c.BaseURL == "" {
-		return errors.New("url is required when oidc is enabled")
+	if _, err := zerolog.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("invalid loglevel: %w", err))
+	}
+
+	if c.DataDir == "" {
+		errs = append(errs, errors.New("datadir is required"))
+	}
+
+	for _, trustedProxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(trustedProxy); err != nil {
+			errs = append(errs, fmt.Errorf("trustedproxies: %w", err))
+		}
 	}
 
+	addErrors("node", c.Node.validate())
+	addErrors("oidc", c.OIDC.Validate())
+	addErrors("credentialprofiles", validateCredentialProfiles(c.CredentialProfiles))
+	addErrors("serviceprofiles", validateServiceProfiles(c.ServiceProfiles))
+	addErrors("expiry", c.Expiry.Validate())
+	addErrors("registration", c.Registration.Validate())
+	addErrors("policy", c.Policy.Validate())
+	addErrors("proxy", c.Proxy.Validate())
+
+	if c.Proxy.RequiresRoles() && !c.OIDC.Enabled {
+		errs = append(errs, errors.New("proxy config error: roles require oidc to be enabled"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ipExtractor returns how the IP address of clients is determined. The X-Forwarded-For header is only used
+// if the request comes from one of the trusted proxies, otherwise the address of the connection is used.
+func (c Config) ipExtractor() echo.IPExtractor {
+	if len(c.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, trustedProxy := range c.TrustedProxies {
+		// Checked by Validate
+		_, ipRange, _ := net.ParseCIDR(trustedProxy)
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func (n Node) validate() error {
+	var errs []error
+	if n.Address == "" {
+		errs = append(errs, errors.New("address is required"))
+	} else if err := validateHTTPURL(n.Address); err != nil {
+		errs = append(errs, fmt.Errorf("address: %w", err))
+	}
+	if n.Auth.KeyFile != "" {
+		if n.Auth.User == "" {
+			errs = append(errs, errors.New("auth.user is required with auth.keyfile"))
+		}
+		if n.Auth.Audience == "" {
+			errs = append(errs, errors.New("auth.audience is required with auth.keyfile"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateCredentialProfiles(profiles []model.CredentialProfile) error {
+	var errs []error
+	for i, profile := range profiles {
+		profile = credentialprofile.WithDefaults(profile)
+		errs = append(errs, validation.Prefix(fmt.Sprintf("profile %d", i), credentialprofile.Validate(profile))...)
+		for _, other := range profiles[:i] {
+			if profile.ID != "" && credentialprofile.WithDefaults(other).ID == profile.ID {
+				errs = append(errs, fmt.Errorf("profile %d: duplicate id %s, set id to distinguish profiles of the same type", i, profile.ID))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateServiceProfiles(profiles []model.ServiceProfile) error {
+	var errs []error
+	for i, profile := range profiles {
+		if profile.Type == "" {
+			errs = append(errs, fmt.Errorf("profile %d: type is required", i))
+		}
+		for _, other := range profiles[:i] {
+			if profile.Type != "" && other.Type == profile.Type {
+				errs = append(errs, fmt.Errorf("profile %d: duplicate of another profile for %s", i, profile.Type))
+				break
+			}
+		}
+		if len(profile.RequiredKeys) > 0 && !profile.Compound {
+			errs = append(errs, fmt.Errorf("profile %d: requiredkeys only applies to compound services", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateHTTPURL checks that the given value is an absolute HTTP(S) URL.
+func validateHTTPURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("must be an absolute http or https URL: %s", value)
+	}
 	return nil
 }
 
 func (c Config) Print() {
+	data, _ := json.Marshal(c.masked())
+	logger.Info().Msgf("Config: %s", string(data))
+}
+
+// masked returns a copy of the config with secrets masked, so it can be printed.
+func (c Config) masked() Config {
 	maskedCopy := c
-	if len(maskedCopy.OIDC.Client.Secret) > 0 {
-		maskedCopy.OIDC.Client.Secret = "*****"
+	maskSecrets(reflect.ValueOf(&maskedCopy).Elem())
+	return maskedCopy
+}
+
+// secretTag marks config fields that contain secrets (`secret:"true"`). They're masked when the config is printed,
+// and can be read from a file (see resolveSecrets).
+const secretTag = "secret"
+
+func maskSecrets(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			maskSecrets(value.Field(i))
+		} else if field.Tag.Get(secretTag) == "true" && value.Field(i).Len() > 0 {
+			value.Field(i).SetString("*****")
+		}
 	}
-	data, _ := json.Marshal(maskedCopy)
-	logger.Info().Msgf("Config: %s", string(data))
 }
 
-func loadConfig() Config {
-	flagset := loadFlagSet(os.Args[1:])
+// secretKeys returns the koanf keys of the secret fields of the given config struct type.
+func secretKeys(prefix string, configType reflect.Type) []string {
+	var result []string
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		key := field.Tag.Get("koanf")
+		if key == "" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			result = append(result, secretKeys(prefix+key+".", field.Type)...)
+		} else if field.Tag.Get(secretTag) == "true" {
+			result = append(result, prefix+key)
+		}
+	}
+	return result
+}
 
+// resolveSecrets replaces secrets that are given as file, following the Docker and Kubernetes secret conventions:
+//   - the <key>_file property (e.g. oidc.client.secret_file) or <KEY>_FILE environment variable (e.g. NUTS_OIDC_CLIENT_SECRET_FILE)
+//     contains the path of the file containing the secret;
+//   - the value of the secret itself is a file:// reference, e.g. file:///run/secrets/oidc_client_secret.
+//
+// Trailing newlines are removed from the file contents.
+func resolveSecrets(k *koanf.Koanf) error {
+	for _, key := range secretKeys("", reflect.TypeOf(Config{})) {
+		// The environment variable ends up as key.file, since underscores in environment variables are delimiters
+		var path string
+		for _, fileKey := range []string{key + "_file", key + ".file"} {
+			if k.Exists(fileKey) {
+				path = k.String(fileKey)
+				k.Delete(fileKey)
+			}
+		}
+		value := k.Get(key)
+		if path != "" {
+			if value, isString := value.(string); isString && value != "" {
+				return fmt.Errorf("%s: can't be set both as value and as file", key)
+			}
+		} else if value, isString := value.(string); isString && strings.HasPrefix(value, "file://") {
+			path = strings.TrimPrefix(value, "file://")
+		}
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: unable to read secret file: %w", key, err)
+		}
+		k.Delete(key)
+		_ = k.Set(key, strings.TrimRight(string(data), "\r\n"))
+	}
+	return nil
+}
+
+// loadConfig loads the config at startup, exiting when it can't be loaded.
+func loadConfig(configFilePath string) Config {
+	if _, err := os.Stat(configFilePath); err == nil {
+		logger.Info().Msgf("Loading config from file: %s", configFilePath)
+	} else {
+		logger.Info().Msgf("Using default config because no file was found at: %s", configFilePath)
+	}
+	config, err := readConfig(configFilePath)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("unable to load config")
+	}
+	return config
+}
+
+// readConfig reads the config from the given file (if it exists) and the environment, on top of the default config.
+func readConfig(configFilePath string) (Config, error) {
 	var k = koanf.New(".")
 
-	// Prepare koanf for parsing the config file
-	configFilePath := resolveConfigFile(flagset)
 	// Check if the file exists
 	if _, err := os.Stat(configFilePath); err == nil {
-		logger.Info().Msgf("Loading config from file: %s", configFilePath)
 		if err := k.Load(file.Provider(configFilePath), yaml.Parser()); err != nil {
-			logger.Fatal().Msgf("error while loading config from file: %v", err)
+			return Config{}, fmt.Errorf("error while loading config from file: %w", err)
 		}
-	} else {
-		logger.Info().Msgf("Using default config because no file was found at: %s", configFilePath)
 	}
 
 	// load env flags, can't return error
 	_ = k.Load(envProvider(), nil)
 
+	if err := resolveSecrets(k); err != nil {
+		return Config{}, err
+	}
+
 	config := defaultConfig()
 
 	// Unmarshal values of the config file into the config struct, potentially replacing default values
 	if err := k.Unmarshal("", &config); err != nil {
-		log.Fatalf("error while unmarshalling config: %v", err)
+		return Config{}, fmt.Errorf("error while unmarshalling config: %w", err)
 	}
 
 	// Load the API key
 	if len(config.Node.Auth.KeyFile) > 0 {
 		bytes, err := os.ReadFile(config.Node.Auth.KeyFile)
 		if err != nil {
-			log.Fatalf("error while reading private key file: %v", err)
+			return Config{}, fmt.Errorf("error while reading private key file: %w", err)
 		}
 		config.apiKey, err = pemToPrivateKey(bytes)
 		if err != nil {
-			log.Fatalf("error while decoding private key file: %v", err)
-		}
-		if len(config.Node.Auth.User) == 0 {
-			log.Fatal("node.auth.user config is required with node.auth.keyfile")
-		}
-		if len(config.Node.Auth.Audience) == 0 {
-			log.Fatal("node.auth.audience config is required with node.auth.keyfile")
+			return Config{}, fmt.Errorf("error while decoding private key file: %w", err)
 		}
 	}
 
-	return config
-}
-
-func loadFlagSet(args []string) *pflag.FlagSet {
-	f := pflag.NewFlagSet("config", pflag.ContinueOnError)
-	f.String(configFileFlag, defaultConfigFile, "Application config file")
-	f.Usage = func() {
-		fmt.Println(f.FlagUsages())
-		os.Exit(0)
-	}
-	f.Parse(args)
-	return f
+	return config, nil
 }
 
 // resolveConfigFile resolves the path of the config file using the following sources:
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/nuts-foundation/nuts-admin/validation"
+	"github.com/spf13/pflag"
+)
+
+// runConfigPrint loads the config like the server does at startup, and prints the effective config with secrets masked.
+// It returns the exit code: 0 if the config could be loaded, 2 if it can't be loaded.
+func runConfigPrint(out io.Writer, flags *pflag.FlagSet, _ []string) int {
+	configFilePath := resolveConfigFile(flags)
+	config, err := readConfig(configFilePath)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "unable to load config from %s: %s\n", configFilePath, err)
+		return 2
+	}
+	printConfig(out, config)
+	return 0
+}
+
+// runConfigCheck loads the config like the server does at startup, prints the effective config with secrets masked and validates it.
+// It returns the exit code: 0 if the config is valid, 1 if it's invalid, 2 if it can't be loaded.
+func runConfigCheck(out io.Writer, configFilePath string) int {
+	config, err := readConfig(configFilePath)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "unable to load config from %s: %s\n", configFilePath, err)
+		return 2
+	}
+	printConfig(out, config)
+	err = config.Validate()
+	if err == nil {
+		_, _ = fmt.Fprintf(out, "%s: OK\n", configFilePath)
+		return 0
+	}
+	_, _ = fmt.Fprintf(out, "%s:\n", configFilePath)
+	for _, problem := range validation.Errors(err) {
+		_, _ = fmt.Fprintf(out, "  %s\n", problem)
+	}
+	return 1
+}
+
+func printConfig(out io.Writer, config Config) {
+	data, _ := json.MarshalIndent(config.masked(), "", "  ")
+	_, _ = fmt.Fprintf(out, "%s\n", data)
+}
@@ -1,11 +1,22 @@
 package main
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nuts-foundation/nuts-admin/model"
 	"github.com/nuts-foundation/nuts-admin/oidc"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 type MessageHook string
@@ -36,5 +47,182 @@ func TestConfig_Print(t *testing.T) {
 			assert.NotContains(t, string(capturedMessage), "changeme")
 			assert.Contains(t, string(capturedMessage), `"Secret":"*****"`)
 		})
+		t.Run("SMTP password set", func(t *testing.T) {
+			config := Config{}
+			config.Expiry.SMTP.Password = "changeme"
+			config.Print()
+			assert.NotContains(t, string(capturedMessage), "changeme")
+			assert.Contains(t, string(capturedMessage), `"Password":"*****"`)
+		})
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		assert.NoError(t, defaultConfig().Validate())
+	})
+	t.Run("all problems are reported", func(t *testing.T) {
+		config := defaultConfig()
+		config.HTTPPort = 0
+		config.Node.Address = "nutsnode:8081"
+		config.Node.Auth.KeyFile = "key.pem"
+		config.CredentialProfiles = []model.CredentialProfile{{Issuer: "https://issuer.example.com"}}
+		config.OIDC.Enabled = true
+
+		err := config.Validate()
+
+		assert.EqualError(t, err, strings.Join([]string{
+			"port must be between 1 and 65535: 0",
+			"url is required when oidc is enabled",
+			"node config error: address: must be an absolute http or https URL: nutsnode:8081",
+			"node config error: auth.user is required with auth.keyfile",
+			"node config error: auth.audience is required with auth.keyfile",
+			"oidc config error: metadata is required",
+			"oidc config error: client.id is required",
+			"oidc config error: client.secret is required",
+			"credentialprofiles config error: profile 0: type is required",
+		}, "\n"))
+	})
+	t.Run("duplicate profiles", func(t *testing.T) {
+		config := defaultConfig()
+		config.CredentialProfiles = []model.CredentialProfile{
+			{Type: "NutsOrganizationCredential", Issuer: "https://issuer.example.com"},
+			{Type: "NutsOrganizationCredential", Issuer: "https://issuer.example.com"},
+		}
+		config.ServiceProfiles = append(config.ServiceProfiles, model.ServiceProfile{Type: "fhir"})
+
+		err := config.Validate()
+
+		assert.ErrorContains(t, err, "credentialprofiles config error: profile 1: duplicate id NutsOrganizationCredential")
+		assert.ErrorContains(t, err, "serviceprofiles config error: profile 2: duplicate of another profile for fhir")
+	})
+}
+
+func TestConfig_ipExtractor(t *testing.T) {
+	request := func(remoteAddr string) *http.Request {
+		result := httptest.NewRequest(http.MethodGet, "/", nil)
+		result.RemoteAddr = remoteAddr
+		result.Header.Set(echo.HeaderXForwardedFor, "203.0.113.1")
+		return result
+	}
+
+	t.Run("no trusted proxies", func(t *testing.T) {
+		extractor := defaultConfig().ipExtractor()
+
+		assert.Equal(t, "10.0.0.1", extractor(request("10.0.0.1:1234")))
+	})
+	t.Run("trusted proxy", func(t *testing.T) {
+		config := defaultConfig()
+		config.TrustedProxies = []string{"10.0.0.0/24"}
+		extractor := config.ipExtractor()
+
+		assert.Equal(t, "203.0.113.1", extractor(request("10.0.0.1:1234")))
+		assert.Equal(t, "10.0.1.1", extractor(request("10.0.1.1:1234")))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		config := defaultConfig()
+		config.TrustedProxies = []string{"10.0.0.1"}
+
+		assert.EqualError(t, config.Validate(), "trustedproxies: invalid CIDR address: 10.0.0.1")
+	})
+}
+
+func TestReadConfig(t *testing.T) {
+	t.Run("node auth audience", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("node:\n  auth:\n    audience: nutsnode\n"), 0644))
+
+		config, err := readConfig(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, "nutsnode", config.Node.Auth.Audience)
+	})
+}
+
+func TestReadConfig_secrets(t *testing.T) {
+	writeFile := func(t *testing.T, name string, contents string) string {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+		return path
+	}
+	secretFile := writeFile(t, "secret", "changeme\n")
+
+	t.Run("secret keys", func(t *testing.T) {
+		assert.Equal(t, []string{"expiry.smtp.password", "oidc.client.secret"}, secretKeys("", reflect.TypeOf(Config{})))
+	})
+	t.Run("_file property", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "oidc:\n  client:\n    secret_file: "+secretFile+"\n")
+
+		config, err := readConfig(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, "changeme", config.OIDC.Client.Secret)
+	})
+	t.Run("_FILE environment variable", func(t *testing.T) {
+		t.Setenv("NUTS_EXPIRY_SMTP_PASSWORD_FILE", secretFile)
+
+		config, err := readConfig(filepath.Join(t.TempDir(), "config.yaml"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "changeme", config.Expiry.SMTP.Password)
+	})
+	t.Run("file:// reference", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "oidc:\n  client:\n    secret: file://"+secretFile+"\n")
+
+		config, err := readConfig(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, "changeme", config.OIDC.Client.Secret)
+	})
+	t.Run("both value and file", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "oidc:\n  client:\n    secret: other\n    secret_file: "+secretFile+"\n")
+
+		_, err := readConfig(path)
+
+		assert.EqualError(t, err, "oidc.client.secret: can't be set both as value and as file")
+	})
+	t.Run("file doesn't exist", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "oidc:\n  client:\n    secret: file:///non-existing\n")
+
+		_, err := readConfig(path)
+
+		assert.ErrorContains(t, err, "oidc.client.secret: unable to read secret file")
+	})
+}
+
+func TestRunConfigCheck(t *testing.T) {
+	writeConfig := func(t *testing.T, contents string) string {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+		return path
+	}
+	t.Run("valid", func(t *testing.T) {
+		path := writeConfig(t, "oidc:\n  client:\n    secret: changeme\n")
+		out := new(bytes.Buffer)
+
+		exitCode := runConfigCheck(out, path)
+
+		assert.Equal(t, 0, exitCode)
+		assert.Contains(t, out.String(), path+": OK")
+		assert.NotContains(t, out.String(), "changeme")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		path := writeConfig(t, "port: 0\nloglevel: loud\n")
+		out := new(bytes.Buffer)
+
+		exitCode := runConfigCheck(out, path)
+
+		assert.Equal(t, 1, exitCode)
+		assert.Contains(t, out.String(), "  port must be between 1 and 65535: 0\n")
+		assert.Contains(t, out.String(), "  invalid loglevel")
+	})
+	t.Run("unreadable", func(t *testing.T) {
+		path := writeConfig(t, "port: [")
+		out := new(bytes.Buffer)
+
+		exitCode := runConfigCheck(out, path)
+
+		assert.Equal(t, 2, exitCode)
+		assert.Contains(t, out.String(), "unable to load config")
 	})
 }
@@ -0,0 +1,212 @@
+// Package credentialprofile manages the credential profiles subjects can request credentials for using OpenID4VCI.
+// Profiles come from the config file (read-only) or are managed through the API and persisted in the data directory.
+package credentialprofile
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/nuts-foundation/nuts-admin/store"
+)
+
+// ErrInvalidProfile is returned when a credential profile is incomplete or invalid.
+var ErrInvalidProfile = errors.New("invalid credential profile")
+
+// ErrNotFound is returned when a credential profile doesn't exist.
+var ErrNotFound = errors.New("credential profile not found")
+
+// ErrExists is returned when a credential profile is created with the ID of an existing profile.
+var ErrExists = errors.New("credential profile already exists")
+
+// ErrReadOnly is returned when a credential profile from the config file is changed or deleted.
+var ErrReadOnly = errors.New("credential profile is configured in the config file and can't be changed")
+
+// ErrSubjectNotAllowed is returned when a subject requests a credential it's not allowed to request.
+var ErrSubjectNotAllowed = errors.New("subject is not allowed to request credentials of this profile")
+
+var didMethodPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+type Service struct {
+	// Configured holds the profiles from the config file, they're replaced when the config file is reloaded.
+	Configured *atomic.Pointer[[]model.CredentialProfile]
+	// Managed holds the profiles that are managed through the API.
+	Managed *store.Collection[model.CredentialProfile]
+}
+
+// List returns the profiles from the config file, followed by the managed profiles.
+func (s Service) List() []model.CredentialProfile {
+	result := make([]model.CredentialProfile, 0)
+	for _, profile := range *s.Configured.Load() {
+		profile = WithDefaults(profile)
+		profile.Managed = false
+		result = append(result, profile)
+	}
+	for _, profile := range s.Managed.List() {
+		profile.Managed = true
+		result = append(result, profile)
+	}
+	return result
+}
+
+// Get returns the profile with the given ID, or ErrNotFound.
+func (s Service) Get(id string) (*model.CredentialProfile, error) {
+	for _, profile := range s.List() {
+		if profile.ID == id {
+			return &profile, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Create adds a managed profile. If the profile has no ID, one is generated.
+func (s Service) Create(profile model.CredentialProfile) (*model.CredentialProfile, error) {
+	if profile.ID == "" {
+		profile.ID = uuid.NewString()
+	}
+	profile = WithDefaults(profile)
+	profile.Managed = true
+	if err := Validate(profile); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProfile, err)
+	}
+	if s.isConfigured(profile.ID) {
+		return nil, fmt.Errorf("%w: %s", ErrExists, profile.ID)
+	}
+	err := s.Managed.Update(func(profiles []model.CredentialProfile) ([]model.CredentialProfile, error) {
+		if slices.ContainsFunc(profiles, func(other model.CredentialProfile) bool { return other.ID == profile.ID }) {
+			return nil, fmt.Errorf("%w: %s", ErrExists, profile.ID)
+		}
+		return append(profiles, profile), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Update replaces the managed profile with the given ID. The ID of the profile can't be changed.
+func (s Service) Update(id string, profile model.CredentialProfile) (*model.CredentialProfile, error) {
+	if profile.ID != "" && profile.ID != id {
+		return nil, fmt.Errorf("%w: id can't be changed", ErrInvalidProfile)
+	}
+	profile.ID = id
+	profile = WithDefaults(profile)
+	profile.Managed = true
+	if err := Validate(profile); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProfile, err)
+	}
+	err := s.updateManaged(id, func(profiles []model.CredentialProfile, index int) []model.CredentialProfile {
+		profiles[index] = profile
+		return profiles
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Delete removes the managed profile with the given ID.
+func (s Service) Delete(id string) error {
+	return s.updateManaged(id, func(profiles []model.CredentialProfile, index int) []model.CredentialProfile {
+		return slices.Delete(profiles, index, index+1)
+	})
+}
+
+func (s Service) updateManaged(id string, fn func(profiles []model.CredentialProfile, index int) []model.CredentialProfile) error {
+	if s.isConfigured(id) {
+		return fmt.Errorf("%w: %s", ErrReadOnly, id)
+	}
+	return s.Managed.Update(func(profiles []model.CredentialProfile) ([]model.CredentialProfile, error) {
+		index := slices.IndexFunc(profiles, func(profile model.CredentialProfile) bool { return profile.ID == id })
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return fn(profiles, index), nil
+	})
+}
+
+func (s Service) isConfigured(id string) bool {
+	return slices.ContainsFunc(*s.Configured.Load(), func(profile model.CredentialProfile) bool {
+		return WithDefaults(profile).ID == id
+	})
+}
+
+// WithDefaults returns the profile with the ID, name and credential configuration ID defaulting to the type.
+func WithDefaults(profile model.CredentialProfile) model.CredentialProfile {
+	if profile.ID == "" {
+		profile.ID = profile.Type
+	}
+	if profile.Name == "" {
+		profile.Name = profile.Type
+	}
+	if profile.CredentialConfigurationID == "" {
+		profile.CredentialConfigurationID = profile.Type
+	}
+	return profile
+}
+
+// Validate checks the profile is complete and its URLs and DID method are valid. The returned error joins all problems (see errors.Join).
+func Validate(profile model.CredentialProfile) error {
+	var errs []error
+	if profile.Type == "" {
+		errs = append(errs, errors.New("type is required"))
+	}
+	if profile.Issuer == "" {
+		errs = append(errs, errors.New("issuer is required"))
+	} else if !strings.HasPrefix(profile.Issuer, "did:") {
+		if err := validateHTTPURL(profile.Issuer); err != nil {
+			errs = append(errs, fmt.Errorf("issuer %w", err))
+		}
+	}
+	if profile.AuthorizationServer != "" {
+		if err := validateHTTPURL(profile.AuthorizationServer); err != nil {
+			errs = append(errs, fmt.Errorf("authorizationserver %w", err))
+		}
+	}
+	if profile.DIDMethod != "" && !didMethodPattern.MatchString(profile.DIDMethod) {
+		errs = append(errs, fmt.Errorf("didmethod must be a DID method name without the did: prefix, e.g. web: %s", profile.DIDMethod))
+	}
+	if slices.Contains(profile.AllowedSubjects, "") {
+		errs = append(errs, errors.New("allowedsubjects can't contain an empty subject"))
+	}
+	return errors.Join(errs...)
+}
+
+func validateHTTPURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("must be a DID or an absolute http or https URL: %s", value)
+	}
+	return nil
+}
+
+// Apply fills in the credential request of the subject from the profile: the issuer, authorization server and,
+// if the request doesn't specify them, the authorization details. It checks the subject may request the credential,
+// and the wallet DID has the required DID method.
+func Apply(profile model.CredentialProfile, subjectID string, request identity.CredentialRequest) (identity.CredentialRequest, error) {
+	if len(profile.AllowedSubjects) > 0 && !slices.Contains(profile.AllowedSubjects, subjectID) {
+		return request, fmt.Errorf("%w: %s", ErrSubjectNotAllowed, subjectID)
+	}
+	if profile.DIDMethod != "" && !strings.HasPrefix(request.WalletDID, "did:"+profile.DIDMethod+":") {
+		return request, fmt.Errorf("%w: wallet_did must be a did:%s DID", identity.ErrInvalidCredentialRequest, profile.DIDMethod)
+	}
+	request.ProfileID = profile.ID
+	request.Issuer = profile.Issuer
+	request.AuthorizationServer = profile.AuthorizationServer
+	if len(request.AuthorizationDetails) == 0 {
+		request.AuthorizationDetails = []map[string]interface{}{
+			{
+				"type":                        "openid_credential",
+				"credential_configuration_id": profile.CredentialConfigurationID,
+			},
+		}
+	}
+	return request, nil
+}
@@ -0,0 +1,186 @@
+package credentialprofile
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/nuts-foundation/nuts-admin/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	setup := func(t *testing.T) (Service, string) {
+		configured := &atomic.Pointer[[]model.CredentialProfile]{}
+		configured.Store(&[]model.CredentialProfile{
+			{Type: "NutsOrganizationCredential", Issuer: "did:web:example.com:iam:issuer"},
+		})
+		path := filepath.Join(t.TempDir(), "credential-profiles.json")
+		managed, err := store.Open[model.CredentialProfile](path)
+		require.NoError(t, err)
+		return Service{Configured: configured, Managed: managed}, path
+	}
+	profile := model.CredentialProfile{
+		ID:              "healthcare-provider",
+		Type:            "HealthcareProviderCredential",
+		Issuer:          "https://issuer.example.com",
+		DIDMethod:       "web",
+		AllowedSubjects: []string{"hospital"},
+	}
+
+	t.Run("list", func(t *testing.T) {
+		service, _ := setup(t)
+		_, err := service.Create(profile)
+		require.NoError(t, err)
+
+		profiles := service.List()
+
+		require.Len(t, profiles, 2)
+		assert.Equal(t, model.CredentialProfile{
+			ID:                        "NutsOrganizationCredential",
+			Type:                      "NutsOrganizationCredential",
+			Issuer:                    "did:web:example.com:iam:issuer",
+			Name:                      "NutsOrganizationCredential",
+			CredentialConfigurationID: "NutsOrganizationCredential",
+		}, profiles[0])
+		assert.Equal(t, "healthcare-provider", profiles[1].ID)
+		assert.True(t, profiles[1].Managed)
+	})
+	t.Run("create", func(t *testing.T) {
+		t.Run("persisted", func(t *testing.T) {
+			service, path := setup(t)
+
+			created, err := service.Create(profile)
+
+			require.NoError(t, err)
+			assert.Equal(t, "HealthcareProviderCredential", created.Name)
+			reopened, err := store.Open[model.CredentialProfile](path)
+			require.NoError(t, err)
+			assert.Equal(t, []model.CredentialProfile{*created}, reopened.List())
+		})
+		t.Run("ID is generated", func(t *testing.T) {
+			service, _ := setup(t)
+			withoutID := profile
+			withoutID.ID = ""
+
+			created, err := service.Create(withoutID)
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, created.ID)
+		})
+		t.Run("invalid", func(t *testing.T) {
+			service, _ := setup(t)
+
+			_, err := service.Create(model.CredentialProfile{Issuer: "issuer.example.com", DIDMethod: "did:web"})
+
+			assert.ErrorIs(t, err, ErrInvalidProfile)
+			assert.ErrorContains(t, err, "type is required")
+			assert.ErrorContains(t, err, "issuer must be a DID or an absolute http or https URL")
+			assert.ErrorContains(t, err, "didmethod must be a DID method name")
+		})
+		t.Run("ID of a configured profile", func(t *testing.T) {
+			service, _ := setup(t)
+			duplicate := profile
+			duplicate.ID = "NutsOrganizationCredential"
+
+			_, err := service.Create(duplicate)
+
+			assert.ErrorIs(t, err, ErrExists)
+		})
+		t.Run("ID of a managed profile", func(t *testing.T) {
+			service, _ := setup(t)
+			_, err := service.Create(profile)
+			require.NoError(t, err)
+
+			_, err = service.Create(profile)
+
+			assert.ErrorIs(t, err, ErrExists)
+		})
+	})
+	t.Run("update", func(t *testing.T) {
+		service, _ := setup(t)
+		_, err := service.Create(profile)
+		require.NoError(t, err)
+		updated := profile
+		updated.ID = ""
+		updated.Description = "Issued by the healthcare authority"
+
+		result, err := service.Update(profile.ID, updated)
+
+		require.NoError(t, err)
+		assert.Equal(t, profile.ID, result.ID)
+		stored, err := service.Get(profile.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Issued by the healthcare authority", stored.Description)
+	})
+	t.Run("delete", func(t *testing.T) {
+		service, _ := setup(t)
+		_, err := service.Create(profile)
+		require.NoError(t, err)
+
+		require.NoError(t, service.Delete(profile.ID))
+
+		_, err = service.Get(profile.ID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, service.Delete(profile.ID), ErrNotFound)
+	})
+	t.Run("configured profiles are read-only", func(t *testing.T) {
+		service, _ := setup(t)
+
+		_, err := service.Update("NutsOrganizationCredential", model.CredentialProfile{Type: "NutsOrganizationCredential", Issuer: "https://issuer.example.com"})
+		assert.ErrorIs(t, err, ErrReadOnly)
+		assert.ErrorIs(t, service.Delete("NutsOrganizationCredential"), ErrReadOnly)
+	})
+}
+
+func TestApply(t *testing.T) {
+	profile := WithDefaults(model.CredentialProfile{
+		ID:                  "healthcare-provider",
+		Type:                "HealthcareProviderCredential",
+		Issuer:              "https://issuer.example.com",
+		AuthorizationServer: "https://auth.example.com",
+		DIDMethod:           "web",
+		AllowedSubjects:     []string{"hospital"},
+	})
+	request := identity.CredentialRequest{
+		WalletDID:   "did:web:example.com:iam:hospital",
+		RedirectURI: "https://admin.example.com/",
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		result, err := Apply(profile, "hospital", request)
+
+		require.NoError(t, err)
+		assert.Equal(t, "healthcare-provider", result.ProfileID)
+		assert.Equal(t, "https://issuer.example.com", result.Issuer)
+		assert.Equal(t, "https://auth.example.com", result.AuthorizationServer)
+		assert.Equal(t, []map[string]interface{}{
+			{"type": "openid_credential", "credential_configuration_id": "HealthcareProviderCredential"},
+		}, result.AuthorizationDetails)
+	})
+	t.Run("authorization details of the request are kept", func(t *testing.T) {
+		withDetails := request
+		withDetails.AuthorizationDetails = []map[string]interface{}{{"type": "openid_credential", "format": "jwt_vc_json"}}
+
+		result, err := Apply(profile, "hospital", withDetails)
+
+		require.NoError(t, err)
+		assert.Equal(t, withDetails.AuthorizationDetails, result.AuthorizationDetails)
+	})
+	t.Run("subject not allowed", func(t *testing.T) {
+		_, err := Apply(profile, "pharmacy", request)
+
+		assert.ErrorIs(t, err, ErrSubjectNotAllowed)
+	})
+	t.Run("wrong DID method", func(t *testing.T) {
+		withOtherDID := request
+		withOtherDID.WalletDID = "did:nuts:123"
+
+		_, err := Apply(profile, "hospital", withOtherDID)
+
+		assert.ErrorIs(t, err, identity.ErrInvalidCredentialRequest)
+	})
+}
@@ -0,0 +1,162 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/nuts-foundation/go-nuts-client/nuts/discovery"
+	"github.com/nuts-foundation/nuts-admin/pe"
+)
+
+// ErrServiceNotFound is returned when the Nuts node doesn't know the discovery service.
+var ErrServiceNotFound = errors.New("discovery service not found")
+
+// typePath is the JSONPath input descriptors use to select the credential type.
+const typePath = "$.type"
+
+// Definition is a readable view of a discovery service definition, derived from its presentation definition.
+type Definition struct {
+	ID       string `json:"id"`
+	Endpoint string `json:"endpoint"`
+	// PresentationMaxValidity is the maximum validity of registered presentations, in seconds.
+	PresentationMaxValidity int `json:"presentation_max_validity"`
+	// Formats lists the credential and presentation formats the discovery service accepts.
+	Formats []Format `json:"formats"`
+	// Credentials lists the credentials a registration must contain, one per input descriptor.
+	Credentials []CredentialRequirement `json:"credentials"`
+}
+
+// Format is a credential or presentation format accepted by a discovery service, e.g. jwt_vp.
+type Format struct {
+	Format     string   `json:"format"`
+	Algorithms []string `json:"algorithms,omitempty"`
+	ProofTypes []string `json:"proof_types,omitempty"`
+}
+
+// CredentialRequirement describes a credential required by an input descriptor.
+type CredentialRequirement struct {
+	InputDescriptorID string `json:"input_descriptor_id"`
+	Name              string `json:"name,omitempty"`
+	Purpose           string `json:"purpose,omitempty"`
+	// CredentialTypes contains the credential types the input descriptor accepts.
+	CredentialTypes []string `json:"credential_types"`
+	// Fields contains the constraints on the credential, other than its type.
+	Fields []FieldRequirement `json:"fields"`
+}
+
+// FieldRequirement describes a constraint on a credential field.
+type FieldRequirement struct {
+	// ID is the name of the field in search results, if the field has one.
+	ID       string    `json:"id,omitempty"`
+	Path     []string  `json:"path"`
+	Purpose  string    `json:"purpose,omitempty"`
+	Optional bool      `json:"optional"`
+	Filter   pe.Filter `json:"filter,omitempty"`
+}
+
+// GetDefinition returns the definition of the discovery service.
+func (i Service) GetDefinition(ctx context.Context, serviceID string) (*Definition, error) {
+	services, err := i.GetDiscoveryServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		if service.Id == serviceID {
+			return toDefinition(service)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+}
+
+func toDefinition(service discovery.ServiceDefinition) (*Definition, error) {
+	presentationDefinition, err := pe.ParsePresentationDefinition(service.PresentationDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid presentation definition of discovery service %s: %w", service.Id, err)
+	}
+	result := Definition{
+		ID:                      service.Id,
+		Endpoint:                service.Endpoint,
+		PresentationMaxValidity: service.PresentationMaxValidity,
+		Formats:                 toFormats(presentationDefinition.Format),
+		Credentials:             make([]CredentialRequirement, 0),
+	}
+	for _, descriptor := range presentationDefinition.InputDescriptors {
+		requirement := CredentialRequirement{
+			InputDescriptorID: descriptor.ID,
+			Name:              descriptor.Name,
+			Purpose:           descriptor.Purpose,
+			CredentialTypes:   make([]string, 0),
+			Fields:            make([]FieldRequirement, 0),
+		}
+		if descriptor.Constraints != nil {
+			for _, field := range descriptor.Constraints.Fields {
+				if types := credentialTypes(field); len(types) > 0 {
+					requirement.CredentialTypes = append(requirement.CredentialTypes, types...)
+					continue
+				}
+				requirement.Fields = append(requirement.Fields, FieldRequirement{
+					ID:       field.ID,
+					Path:     field.Path,
+					Purpose:  field.Purpose,
+					Optional: field.Optional,
+					Filter:   field.Filter,
+				})
+			}
+		}
+		result.Credentials = append(result.Credentials, requirement)
+	}
+	return &result, nil
+}
+
+// credentialTypes returns the credential types a field selecting $.type requires, if it's such a field.
+func credentialTypes(field pe.Field) []string {
+	if !slices.Contains(field.Path, typePath) || field.Filter == nil {
+		return nil
+	}
+	filter := map[string]interface{}(field.Filter)
+	if contains, ok := filter["contains"].(map[string]interface{}); ok {
+		filter = contains
+	}
+	var result []string
+	if value, ok := filter["const"].(string); ok {
+		result = append(result, value)
+	}
+	options, _ := filter["enum"].([]interface{})
+	for _, option := range options {
+		if value, ok := option.(string); ok {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// toFormats converts the format object of a presentation definition, e.g. {"jwt_vp": {"alg": ["ES256"]}}, sorted by format.
+func toFormats(formats map[string]interface{}) []Format {
+	result := make([]Format, 0, len(formats))
+	for name, value := range formats {
+		format := Format{Format: name}
+		if properties, ok := value.(map[string]interface{}); ok {
+			format.Algorithms = toStrings(properties["alg"])
+			format.ProofTypes = toStrings(properties["proof_type"])
+		}
+		result = append(result, format)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Format < result[j].Format
+	})
+	return result
+}
+
+func toStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var result []string
+	for _, item := range items {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
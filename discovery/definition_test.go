@@ -0,0 +1,62 @@
+package discovery
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/nuts-foundation/go-nuts-client/nuts/discovery"
+	"github.com/nuts-foundation/nuts-admin/pe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_toDefinition(t *testing.T) {
+	data, err := os.ReadFile("../deploy/discovery/dev_eOverdracht2023.json")
+	require.NoError(t, err)
+	var service discovery.ServiceDefinition
+	require.NoError(t, json.Unmarshal(data, &service))
+
+	definition, err := toDefinition(service)
+
+	require.NoError(t, err)
+	assert.Equal(t, "dev:eOverdracht2023", definition.ID)
+	assert.Equal(t, "https://nuts-services.nl/discovery/dev:eOverdracht2023", definition.Endpoint)
+	assert.Equal(t, 2764800, definition.PresentationMaxValidity)
+	assert.Equal(t, []Format{
+		{Format: "jwt_vc", Algorithms: []string{"ES256"}},
+		{Format: "jwt_vp", Algorithms: []string{"ES256"}},
+		{Format: "ldp_vc", ProofTypes: []string{"JsonWebSignature2020"}},
+		{Format: "ldp_vp", ProofTypes: []string{"JsonWebSignature2020"}},
+	}, definition.Formats)
+	require.Len(t, definition.Credentials, 2)
+	organization := definition.Credentials[0]
+	assert.Equal(t, "SelfIssued_NutsOrganizationCredential", organization.InputDescriptorID)
+	assert.Equal(t, []string{"NutsOrganizationCredential"}, organization.CredentialTypes)
+	assert.Equal(t, []FieldRequirement{
+		{ID: "organization.name", Path: []string{"$.credentialSubject.organization.name"}, Filter: pe.Filter{"type": "string"}},
+		{ID: "organization.city", Path: []string{"$.credentialSubject.organization.city"}, Filter: pe.Filter{"type": "string"}},
+	}, organization.Fields)
+	registration := definition.Credentials[1]
+	assert.Equal(t, "Registration parameters", registration.Name)
+	assert.Equal(t, []string{"DiscoveryRegistrationCredential"}, registration.CredentialTypes)
+	assert.Equal(t, "Used by clients to request OAuth2 access tokens.", registration.Fields[0].Purpose)
+}
+
+func Test_credentialTypes(t *testing.T) {
+	t.Run("enum", func(t *testing.T) {
+		field := pe.Field{Path: []string{"$.type"}, Filter: pe.Filter{"enum": []interface{}{"A", "B"}}}
+
+		assert.Equal(t, []string{"A", "B"}, credentialTypes(field))
+	})
+	t.Run("array contains", func(t *testing.T) {
+		field := pe.Field{Path: []string{"$.type"}, Filter: pe.Filter{"type": "array", "contains": map[string]interface{}{"const": "A"}}}
+
+		assert.Equal(t, []string{"A"}, credentialTypes(field))
+	})
+	t.Run("other field", func(t *testing.T) {
+		field := pe.Field{Path: []string{"$.credentialSubject.type"}, Filter: pe.Filter{"const": "A"}}
+
+		assert.Empty(t, credentialTypes(field))
+	})
+}
@@ -4,7 +4,9 @@ import "github.com/nuts-foundation/go-did/vc"
 
 // DIDStatus represents the status of a DID in the discovery service
 type DIDStatus struct {
-	ServiceID     string                      `json:"id"`
-	Active        bool                        `json:"active"`
+	ServiceID string `json:"id"`
+	Active    bool   `json:"active"`
+	// Error contains the reason the Nuts node's last attempt to register or refresh the registration failed.
+	Error         string                      `json:"error,omitempty"`
 	Presentations []vc.VerifiablePresentation `json:"vps"`
 }
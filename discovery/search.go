@@ -0,0 +1,145 @@
+package discovery
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/go-nuts-client/nuts"
+	"github.com/nuts-foundation/go-nuts-client/nuts/discovery"
+)
+
+// DefaultPageSize is the number of search results per page if the query doesn't specify it.
+const DefaultPageSize = 25
+
+// MaxPageSize is the maximum number of search results per page.
+const MaxPageSize = 100
+
+// SearchQuery contains the filters and pagination of a discovery service search. All filters are optional.
+// Filters on credential fields support a leading or trailing * as wildcard, as the Nuts node does.
+type SearchQuery struct {
+	OrganizationName string
+	City             string
+	URA              string
+	// CredentialType only returns registrations that contain a credential of this type.
+	CredentialType string
+	// Page is the 1-based page number.
+	Page     int
+	PageSize int
+}
+
+// SearchPage is a page of discovery service search results.
+type SearchPage struct {
+	Results  []SearchResult `json:"results"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	// Total is the number of results over all pages.
+	Total int `json:"total"`
+}
+
+// SearchResult is a registration on a discovery service.
+type SearchResult struct {
+	// ID is the ID of the registered presentation.
+	ID         string `json:"id"`
+	SubjectDID string `json:"subject_did"`
+	// Fields contains the values of the credential fields the presentation definition of the service selects.
+	Fields                 map[string]interface{} `json:"fields"`
+	CredentialTypes        []string               `json:"credential_types"`
+	RegistrationParameters map[string]interface{} `json:"registration_parameters,omitempty"`
+	// ExpiresAt is the expiry of the registration, after which it's removed from the discovery service unless it's renewed.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Search searches the registrations on the discovery service, sorted by subject DID.
+// The field filters are applied by the Nuts node, the credential type filter and pagination by nuts-admin.
+func (i Service) Search(ctx context.Context, serviceID string, query SearchQuery) (*SearchPage, error) {
+	params := map[string]interface{}{}
+	if query.OrganizationName != "" {
+		params["credentialSubject.organization.name"] = query.OrganizationName
+	}
+	if query.City != "" {
+		params["credentialSubject.organization.city"] = query.City
+	}
+	if query.URA != "" {
+		params["credentialSubject.organization.ura"] = query.URA
+	}
+	registrations, err := i.searchPresentations(ctx, serviceID, params)
+	if err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, result := range registrations {
+		if query.CredentialType != "" && !slices.Contains(result.CredentialTypes, query.CredentialType) {
+			continue
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].SubjectDID < results[j].SubjectDID
+	})
+	return paginate(results, query.Page, query.PageSize), nil
+}
+
+// Registrations returns all registrations on the discovery service, as known by the Nuts node.
+func (i Service) Registrations(ctx context.Context, serviceID string) ([]SearchResult, error) {
+	return i.searchPresentations(ctx, serviceID, map[string]interface{}{})
+}
+
+func (i Service) searchPresentations(ctx context.Context, serviceID string, query map[string]interface{}) ([]SearchResult, error) {
+	httpResponse, err := i.Client.SearchPresentations(ctx, serviceID, &discovery.SearchPresentationsParams{Query: &query})
+	response, err := nuts.ParseResponse(err, httpResponse, discovery.ParseSearchPresentationsResponse)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]SearchResult, 0, len(*response.JSON200))
+	for _, current := range *response.JSON200 {
+		result = append(result, toSearchResult(current))
+	}
+	return result, nil
+}
+
+func toSearchResult(searchResult discovery.SearchResult) SearchResult {
+	result := SearchResult{
+		ID:                     searchResult.Id,
+		SubjectDID:             searchResult.CredentialSubjectId,
+		Fields:                 searchResult.Fields,
+		RegistrationParameters: searchResult.RegistrationParameters,
+		CredentialTypes:        make([]string, 0),
+	}
+	if result.Fields == nil {
+		result.Fields = map[string]interface{}{}
+	}
+	for _, credential := range searchResult.Vp.VerifiableCredential {
+		for _, credentialType := range credential.Type {
+			if credentialType != vc.VerifiableCredentialTypeV1URI() && !slices.Contains(result.CredentialTypes, credentialType.String()) {
+				result.CredentialTypes = append(result.CredentialTypes, credentialType.String())
+			}
+		}
+	}
+	if token := searchResult.Vp.JWT(); token != nil && !token.Expiration().IsZero() {
+		expiresAt := token.Expiration()
+		result.ExpiresAt = &expiresAt
+	}
+	return result
+}
+
+func paginate(results []SearchResult, page int, pageSize int) *SearchPage {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	pageSize = min(pageSize, MaxPageSize)
+	page = max(page, 1)
+	result := SearchPage{
+		Results:  make([]SearchResult, 0),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    len(results),
+	}
+	start := (page - 1) * pageSize
+	if start < len(results) {
+		result.Results = append(result.Results, results[start:min(start+pageSize, len(results))]...)
+	}
+	return &result
+}
@@ -0,0 +1,68 @@
+package discovery
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/go-nuts-client/nuts/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_paginate(t *testing.T) {
+	var results []SearchResult
+	for i := 0; i < 30; i++ {
+		results = append(results, SearchResult{ID: strconv.Itoa(i)})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		page := paginate(results, 0, 0)
+
+		assert.Equal(t, 1, page.Page)
+		assert.Equal(t, DefaultPageSize, page.PageSize)
+		assert.Equal(t, 30, page.Total)
+		assert.Len(t, page.Results, DefaultPageSize)
+	})
+	t.Run("last page", func(t *testing.T) {
+		page := paginate(results, 2, 25)
+
+		require.Len(t, page.Results, 5)
+		assert.Equal(t, "25", page.Results[0].ID)
+	})
+	t.Run("beyond last page", func(t *testing.T) {
+		page := paginate(results, 3, 25)
+
+		assert.Empty(t, page.Results)
+		assert.NotNil(t, page.Results)
+		assert.Equal(t, 30, page.Total)
+	})
+	t.Run("page size is limited", func(t *testing.T) {
+		page := paginate(results, 1, 1000)
+
+		assert.Equal(t, MaxPageSize, page.PageSize)
+	})
+}
+
+func Test_toSearchResult(t *testing.T) {
+	credential, err := vc.ParseVerifiableCredential(`{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"type": ["VerifiableCredential", "NutsUraCredential"],
+		"issuer": "did:web:issuer",
+		"issuanceDate": "2024-01-01T00:00:00Z",
+		"credentialSubject": {"id": "did:web:holder"}
+	}`)
+	require.NoError(t, err)
+
+	result := toSearchResult(discovery.SearchResult{
+		Id:                  "vp-1",
+		CredentialSubjectId: "did:web:holder",
+		Vp:                  vc.VerifiablePresentation{VerifiableCredential: []vc.VerifiableCredential{*credential, *credential}},
+	})
+
+	assert.Equal(t, "vp-1", result.ID)
+	assert.Equal(t, "did:web:holder", result.SubjectDID)
+	assert.Equal(t, []string{"NutsUraCredential"}, result.CredentialTypes)
+	assert.NotNil(t, result.Fields)
+	assert.Nil(t, result.ExpiresAt)
+}
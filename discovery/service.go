@@ -1,7 +1,9 @@
 package discovery
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 
 	"github.com/nuts-foundation/go-nuts-client/nuts"
 	"github.com/nuts-foundation/go-nuts-client/nuts/discovery"
@@ -20,6 +22,29 @@ func (i Service) GetDiscoveryServices(ctx context.Context) ([]discovery.ServiceD
 	return *response.JSON200, nil
 }
 
+// Activate activates the discovery service for the subject, registering it on the discovery server.
+// The registration parameters are optional.
+func (i Service) Activate(ctx context.Context, serviceID string, subjectID string, registrationParameters map[string]interface{}) error {
+	request := map[string]interface{}{}
+	if len(registrationParameters) > 0 {
+		request["registrationParameters"] = registrationParameters
+	}
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpResponse, err := i.Client.ActivateServiceForSubjectWithBody(ctx, serviceID, subjectID, "application/json", bytes.NewReader(requestBody))
+	_, err = nuts.ParseResponse(err, httpResponse, discovery.ParseActivateServiceForSubjectResponse)
+	return err
+}
+
+// Deactivate deactivates the discovery service for the subject, removing its registration from the discovery server.
+func (i Service) Deactivate(ctx context.Context, serviceID string, subjectID string) error {
+	httpResponse, err := i.Client.DeactivateServiceForSubject(ctx, serviceID, subjectID)
+	_, err = nuts.ParseResponse(err, httpResponse, discovery.ParseDeactivateServiceForSubjectResponse)
+	return err
+}
+
 func (i Service) ActivationStatus(ctx context.Context, serviceID string, subjectID string) (*DIDStatus, error) {
 	httpResponse, err := i.Client.GetServiceActivation(ctx, serviceID, subjectID)
 	response, err := nuts.ParseResponse(err, httpResponse, discovery.ParseGetServiceActivationResponse)
@@ -30,6 +55,9 @@ func (i Service) ActivationStatus(ctx context.Context, serviceID string, subject
 		ServiceID: serviceID,
 		Active:    response.JSON200.Activated,
 	}
+	if response.JSON200.Error != nil {
+		result.Error = *response.JSON200.Error
+	}
 	if response.JSON200.Vp != nil {
 		result.Presentations = *response.JSON200.Vp
 	}
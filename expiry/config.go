@@ -0,0 +1,68 @@
+package expiry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Config struct {
+	// Interval is the time between scans. If zero, credentials are not scanned periodically.
+	Interval time.Duration `koanf:"interval"`
+	// Window is how long before expiry a credential is flagged.
+	Window time.Duration `koanf:"window"`
+	// CredentialTypes contains the types of issued credentials that are scanned. Wallet credentials are scanned regardless of type.
+	CredentialTypes []string      `koanf:"credentialtypes"`
+	Webhook         WebhookConfig `koanf:"webhook"`
+	SMTP            SMTPConfig    `koanf:"smtp"`
+}
+
+type WebhookConfig struct {
+	// URL is the endpoint alerts are posted to as JSON. If empty, no webhook is called.
+	URL string `koanf:"url"`
+}
+
+type SMTPConfig struct {
+	// Address is the host:port of the SMTP server. If empty, no mail is sent.
+	Address  string   `koanf:"address"`
+	From     string   `koanf:"from"`
+	To       []string `koanf:"to"`
+	Username string   `koanf:"username"`
+	Password string   `koanf:"password" secret:"true"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Interval: time.Hour,
+		Window:   30 * 24 * time.Hour,
+		CredentialTypes: []string{
+			"NutsOrganizationCredential",
+			"NutsUraCredential",
+		},
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	if c.Interval < 0 {
+		errs = append(errs, errors.New("interval can't be negative"))
+	}
+	if c.Window <= 0 {
+		errs = append(errs, errors.New("window must be positive"))
+	}
+	if c.Webhook.URL != "" {
+		if webhookURL, err := url.Parse(c.Webhook.URL); err != nil || !webhookURL.IsAbs() {
+			errs = append(errs, fmt.Errorf("webhook.url must be an absolute URL: %s", c.Webhook.URL))
+		}
+	}
+	if c.SMTP.Address != "" {
+		if c.SMTP.From == "" {
+			errs = append(errs, errors.New("smtp.from is required when smtp.address is set"))
+		}
+		if len(c.SMTP.To) == 0 {
+			errs = append(errs, errors.New("smtp.to is required when smtp.address is set"))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,242 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/issuer"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/rs/zerolog"
+)
+
+// Source indicates where an expiring credential was found.
+type Source string
+
+const (
+	SourceWallet Source = "wallet"
+	SourceIssued Source = "issued"
+)
+
+// Alert is raised for a credential that expires within the configured window.
+type Alert struct {
+	Source       Source   `json:"source"`
+	CredentialID string   `json:"credential_id"`
+	Types        []string `json:"types"`
+	// Subject is the subject of the wallet the credential is in, or the subject that issued it.
+	Subject        string    `json:"subject"`
+	Issuer         string    `json:"issuer"`
+	ExpirationDate time.Time `json:"expiration_date"`
+}
+
+func (a Alert) key() string {
+	return string(a.Source) + "|" + a.Subject + "|" + a.CredentialID
+}
+
+// Report is the result of the last scan.
+type Report struct {
+	ScannedAt *time.Time `json:"scanned_at,omitempty"`
+	// ExpiringBefore is the end of the window: credentials expiring before this time are flagged.
+	ExpiringBefore *time.Time `json:"expiring_before,omitempty"`
+	Alerts         []Alert    `json:"alerts"`
+	// Error contains the reason the last scan failed, if it failed.
+	Error string `json:"error,omitempty"`
+	// NotificationError contains the reason the notifications of the last scan failed, if they failed.
+	// The alerts are notified again on the next scan.
+	NotificationError string `json:"notification_error,omitempty"`
+}
+
+// Monitor periodically scans wallet and issued credentials for credentials that expire soon.
+// Newly flagged credentials are sent to the notifiers.
+type Monitor struct {
+	config          Config
+	identityService identity.Service
+	issuerService   issuer.Service
+	notifiers       []Notifier
+	logger          zerolog.Logger
+	// smtpPassword is the SMTP password set by a config reload, if any.
+	smtpPassword atomic.Pointer[string]
+
+	// scanMux makes sure scans don't run concurrently, so alerts aren't notified twice.
+	scanMux  sync.Mutex
+	mux      sync.Mutex
+	report   Report
+	notified map[string]bool
+}
+
+func NewMonitor(config Config, identityService identity.Service, issuerService issuer.Service, logger zerolog.Logger) *Monitor {
+	monitor := &Monitor{
+		config:          config,
+		identityService: identityService,
+		issuerService:   issuerService,
+		logger:          logger,
+		report:          Report{Alerts: make([]Alert, 0)},
+		notified:        map[string]bool{},
+	}
+	if config.Webhook.URL != "" {
+		monitor.notifiers = append(monitor.notifiers, WebhookNotifier{URL: config.Webhook.URL})
+	}
+	if config.SMTP.Address != "" {
+		monitor.notifiers = append(monitor.notifiers, SMTPNotifier{Config: config.SMTP, password: &monitor.smtpPassword})
+	}
+	return monitor
+}
+
+// SetSMTPPassword changes the password used to mail alerts, e.g. when the secret file is changed and the config is reloaded.
+func (m *Monitor) SetSMTPPassword(password string) {
+	m.smtpPassword.Store(&password)
+}
+
+// Start scans the credentials immediately and then periodically, until the context is cancelled.
+func (m *Monitor) Start(ctx context.Context) {
+	if m.config.Interval == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			if err := m.Scan(ctx); err != nil {
+				m.logger.Error().Err(err).Msg("credential expiry scan failed")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Report returns the result of the last scan.
+func (m *Monitor) Report() Report {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	result := m.report
+	result.Alerts = slices.Clone(m.report.Alerts)
+	return result
+}
+
+// Scan scans the credentials and notifies about credentials that weren't flagged by earlier scans.
+// Failed notifications are part of the report, and the returned error.
+func (m *Monitor) Scan(ctx context.Context) error {
+	m.scanMux.Lock()
+	defer m.scanMux.Unlock()
+	now := time.Now()
+	expiringBefore := now.Add(m.config.Window)
+	alerts, err := m.scan(ctx, expiringBefore)
+	if err != nil {
+		m.mux.Lock()
+		m.report.ScannedAt = &now
+		m.report.Error = err.Error()
+		m.mux.Unlock()
+		return err
+	}
+	return m.process(ctx, now, expiringBefore, alerts)
+}
+
+// process updates the report with the alerts of a scan, and notifies about the alerts that weren't notified before.
+// If notifying fails, the alerts are notified again on the next scan.
+func (m *Monitor) process(ctx context.Context, scannedAt time.Time, expiringBefore time.Time, alerts []Alert) error {
+	m.mux.Lock()
+	var newAlerts []Alert
+	current := map[string]bool{}
+	for _, alert := range alerts {
+		current[alert.key()] = true
+		if !m.notified[alert.key()] {
+			newAlerts = append(newAlerts, alert)
+		}
+	}
+	// Forget credentials that are no longer flagged (e.g. renewed or expired), so they're not kept forever
+	m.notified = current
+	m.mux.Unlock()
+
+	var errs []error
+	if len(newAlerts) > 0 {
+		for _, notifier := range m.notifiers {
+			if err := notifier.Notify(ctx, newAlerts); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	err := errors.Join(errs...)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.report = Report{
+		ScannedAt:      &scannedAt,
+		ExpiringBefore: &expiringBefore,
+		Alerts:         alerts,
+	}
+	if err != nil {
+		m.report.NotificationError = err.Error()
+		// Notify again on the next scan
+		for _, alert := range newAlerts {
+			delete(m.notified, alert.key())
+		}
+	}
+	return err
+}
+
+func (m *Monitor) scan(ctx context.Context, expiringBefore time.Time) ([]Alert, error) {
+	identities, err := m.identityService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]Alert, 0)
+	for _, currentIdentity := range identities {
+		walletCredentials, err := m.identityService.WalletCredentials(ctx, currentIdentity.Subject)
+		if err != nil {
+			return nil, err
+		}
+		for _, credential := range walletCredentials {
+			if expiresWithin(credential, expiringBefore) {
+				alerts = append(alerts, toAlert(SourceWallet, currentIdentity.Subject, credential))
+			}
+		}
+		for _, issuerDID := range currentIdentity.DIDs {
+			issuedCredentials, err := m.issuerService.GetIssuedCredentials(ctx, issuerDID, m.config.CredentialTypes)
+			if err != nil {
+				return nil, err
+			}
+			for _, credential := range issuedCredentials {
+				if expiresWithin(credential, expiringBefore) {
+					alerts = append(alerts, toAlert(SourceIssued, currentIdentity.Subject, credential))
+				}
+			}
+		}
+	}
+	// Soonest expiry first
+	slices.SortStableFunc(alerts, func(a, b Alert) int {
+		return a.ExpirationDate.Compare(b.ExpirationDate)
+	})
+	return alerts, nil
+}
+
+func expiresWithin(credential model.CredentialWithStatus, expiringBefore time.Time) bool {
+	return credential.Status == "active" &&
+		credential.ExpirationDate != nil &&
+		credential.ExpirationDate.Before(expiringBefore)
+}
+
+func toAlert(source Source, subject string, credential model.CredentialWithStatus) Alert {
+	result := Alert{
+		Source:         source,
+		Subject:        subject,
+		Issuer:         credential.Issuer.String(),
+		ExpirationDate: *credential.ExpirationDate,
+	}
+	if credential.ID != nil {
+		result.CredentialID = credential.ID.String()
+	}
+	for _, credentialType := range credential.Type {
+		if credentialType.String() != "VerifiableCredential" {
+			result.Types = append(result.Types, credentialType.String())
+		}
+	}
+	return result
+}
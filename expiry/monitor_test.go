@@ -0,0 +1,90 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/issuer"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNotifier struct {
+	mux      sync.Mutex
+	err      error
+	received [][]Alert
+}
+
+func (n *testNotifier) Notify(_ context.Context, alerts []Alert) error {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	n.received = append(n.received, alerts)
+	return n.err
+}
+
+func TestMonitor_process(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	expiringBefore := now.Add(time.Hour)
+	otherAlert := testAlert
+	otherAlert.CredentialID = "did:web:example.com#2"
+	setup := func() (*Monitor, *testNotifier) {
+		notifier := &testNotifier{}
+		monitor := NewMonitor(Config{}, identity.Service{}, issuer.Service{}, zerolog.Nop())
+		monitor.notifiers = []Notifier{notifier}
+		return monitor, notifier
+	}
+
+	t.Run("alerts are notified once", func(t *testing.T) {
+		monitor, notifier := setup()
+
+		require.NoError(t, monitor.process(ctx, now, expiringBefore, []Alert{testAlert}))
+		require.NoError(t, monitor.process(ctx, now, expiringBefore, []Alert{testAlert, otherAlert}))
+		require.NoError(t, monitor.process(ctx, now, expiringBefore, []Alert{testAlert, otherAlert}))
+
+		assert.Equal(t, [][]Alert{{testAlert}, {otherAlert}}, notifier.received)
+		report := monitor.Report()
+		assert.Equal(t, []Alert{testAlert, otherAlert}, report.Alerts)
+		assert.Empty(t, report.NotificationError)
+	})
+	t.Run("alerts that are no longer flagged are notified again", func(t *testing.T) {
+		monitor, notifier := setup()
+
+		require.NoError(t, monitor.process(ctx, now, expiringBefore, []Alert{testAlert}))
+		require.NoError(t, monitor.process(ctx, now, expiringBefore, nil))
+		require.NoError(t, monitor.process(ctx, now, expiringBefore, []Alert{testAlert}))
+
+		assert.Len(t, notifier.received, 2)
+	})
+	t.Run("failed notification is reported and notified again", func(t *testing.T) {
+		monitor, notifier := setup()
+		notifier.err = errors.New("webhook failed")
+
+		err := monitor.process(ctx, now, expiringBefore, []Alert{testAlert})
+
+		assert.EqualError(t, err, "webhook failed")
+		report := monitor.Report()
+		assert.Equal(t, "webhook failed", report.NotificationError)
+		assert.Equal(t, []Alert{testAlert}, report.Alerts)
+
+		notifier.err = nil
+		require.NoError(t, monitor.process(ctx, now, expiringBefore, []Alert{testAlert}))
+
+		assert.Equal(t, [][]Alert{{testAlert}, {testAlert}}, notifier.received)
+		assert.Empty(t, monitor.Report().NotificationError)
+	})
+}
+
+func TestMonitor_SetSMTPPassword(t *testing.T) {
+	monitor := NewMonitor(Config{SMTP: SMTPConfig{Address: "localhost:25", Password: "old"}}, identity.Service{}, issuer.Service{}, zerolog.Nop())
+
+	monitor.SetSMTPPassword("new")
+
+	require.Len(t, monitor.notifiers, 1)
+	assert.Equal(t, "new", *monitor.notifiers[0].(SMTPNotifier).password.Load())
+}
@@ -0,0 +1,89 @@
+package expiry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Notifier sends alerts about credentials that expire soon.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// WebhookNotifier posts the alerts as JSON object to a URL.
+type WebhookNotifier struct {
+	URL string
+}
+
+func (w WebhookNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"alerts": alerts,
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook failed: non-OK status: %d", response.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier mails the alerts.
+type SMTPNotifier struct {
+	Config SMTPConfig
+	// password replaces Config.Password when it's changed by a config reload (see Monitor.SetSMTPPassword).
+	password *atomic.Pointer[string]
+}
+
+func (s SMTPNotifier) Notify(_ context.Context, alerts []Alert) error {
+	var auth smtp.Auth
+	if s.Config.Username != "" {
+		password := s.Config.Password
+		if s.password != nil && s.password.Load() != nil {
+			password = *s.password.Load()
+		}
+		host, _, _ := strings.Cut(s.Config.Address, ":")
+		auth = smtp.PlainAuth("", s.Config.Username, password, host)
+	}
+	if err := smtp.SendMail(s.Config.Address, auth, s.Config.From, s.Config.To, s.message(alerts)); err != nil {
+		return fmt.Errorf("sending mail failed: %w", err)
+	}
+	return nil
+}
+
+func (s SMTPNotifier) message(alerts []Alert) []byte {
+	var body bytes.Buffer
+	body.WriteString("From: " + s.Config.From + "\r\n")
+	body.WriteString("To: " + strings.Join(s.Config.To, ", ") + "\r\n")
+	body.WriteString(fmt.Sprintf("Subject: %d credential(s) expiring soon\r\n", len(alerts)))
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	body.WriteString("\r\n")
+	body.WriteString("The following credentials expire soon:\r\n\r\n")
+	for _, alert := range alerts {
+		body.WriteString(fmt.Sprintf("- %s (%s) of subject %s, issued by %s, expires %s\r\n",
+			strings.Join(alert.Types, ", "), alert.Source, alert.Subject, alert.Issuer, alert.ExpirationDate.Format(time.RFC3339)))
+		if alert.CredentialID != "" {
+			body.WriteString("  ID: " + alert.CredentialID + "\r\n")
+		}
+	}
+	return body.Bytes()
+}
@@ -0,0 +1,120 @@
+package expiry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAlert = Alert{
+	Source:         SourceWallet,
+	CredentialID:   "did:web:example.com#1",
+	Types:          []string{"NutsOrganizationCredential"},
+	Subject:        "hospital_x",
+	Issuer:         "did:web:example.com",
+	ExpirationDate: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var received map[string][]Alert
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_ = json.NewDecoder(request.Body).Decode(&received)
+		}))
+		defer server.Close()
+
+		err := WebhookNotifier{URL: server.URL}.Notify(context.Background(), []Alert{testAlert})
+
+		require.NoError(t, err)
+		assert.Equal(t, []Alert{testAlert}, received["alerts"])
+	})
+	t.Run("non-OK status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		err := WebhookNotifier{URL: server.URL}.Notify(context.Background(), []Alert{testAlert})
+
+		assert.EqualError(t, err, "webhook failed: non-OK status: 500")
+	})
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	address, messages := startSMTPStub(t)
+	notifier := SMTPNotifier{Config: SMTPConfig{
+		Address: address,
+		From:    "admin@example.com",
+		To:      []string{"ops@example.com"},
+	}}
+
+	err := notifier.Notify(context.Background(), []Alert{testAlert})
+
+	require.NoError(t, err)
+	message := <-messages
+	assert.Contains(t, message, "Subject: 1 credential(s) expiring soon")
+	assert.Contains(t, message, "- NutsOrganizationCredential (wallet) of subject hospital_x, issued by did:web:example.com, expires 2030-01-01T00:00:00Z")
+	assert.Contains(t, message, "ID: did:web:example.com#1")
+}
+
+// startSMTPStub starts a minimal SMTP server that accepts a single mail, which is sent to the returned channel.
+func startSMTPStub(t *testing.T) (string, chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		write := func(line string) {
+			_, _ = conn.Write([]byte(line + "\r\n"))
+		}
+		write("220 localhost stub")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					messages <- data.String()
+					write("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				write("250 localhost")
+			case command == "DATA":
+				inData = true
+				write("354 Start mail input")
+			case command == "QUIT":
+				write("221 Bye")
+				return
+			default:
+				write("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
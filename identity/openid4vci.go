@@ -0,0 +1,81 @@
+package identity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/nuts-foundation/go-nuts-client/nuts"
+	"github.com/nuts-foundation/go-nuts-client/nuts/iam"
+)
+
+// ErrInvalidCredentialRequest is returned when an OpenID4VCI credential request is incomplete or refers to a DID of another subject.
+var ErrInvalidCredentialRequest = errors.New("invalid credential request")
+
+// CredentialRequest contains the parameters for requesting a credential from an issuer using OpenID4VCI.
+type CredentialRequest struct {
+	// AuthorizationDetails describe the requested credential, e.g. its format and type.
+	AuthorizationDetails []map[string]interface{} `json:"authorization_details"`
+	// Issuer is the DID or URL of the credential issuer.
+	Issuer string `json:"issuer"`
+	// WalletDID is the DID of the subject the credential is issued to.
+	WalletDID string `json:"wallet_did"`
+	// RedirectURI is where the user is sent after the issuer completed the authorization.
+	// For requests made through the API, it's the callback of nuts-admin (see the openid4vci package).
+	RedirectURI string `json:"redirect_uri"`
+	// AuthorizationServer overrides the authorization server from the issuer's metadata.
+	// It's passed on to the Nuts node, which ignores it if it doesn't support choosing the authorization server.
+	AuthorizationServer string `json:"authorization_server,omitempty"`
+	// ProfileID refers to the credential profile the request is made for, see the credentialprofile package.
+	// It's not sent to the Nuts node.
+	ProfileID string `json:"profile_id,omitempty"`
+}
+
+// CredentialRequestResult contains the URL of the issuer's authorization endpoint the user must be redirected to.
+type CredentialRequestResult struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// validate checks the request is complete and the wallet DID is one of the given DIDs of the subject.
+func (r CredentialRequest) validate(subjectDIDs []string) error {
+	if len(r.AuthorizationDetails) == 0 {
+		return fmt.Errorf("%w: authorization_details is required", ErrInvalidCredentialRequest)
+	}
+	if r.Issuer == "" {
+		return fmt.Errorf("%w: issuer is required", ErrInvalidCredentialRequest)
+	}
+	if !slices.Contains(subjectDIDs, r.WalletDID) {
+		return fmt.Errorf("%w: wallet_did must be a DID of the subject", ErrInvalidCredentialRequest)
+	}
+	if redirectURI, err := url.Parse(r.RedirectURI); err != nil || !redirectURI.IsAbs() {
+		return fmt.Errorf("%w: redirect_uri must be an absolute URL", ErrInvalidCredentialRequest)
+	}
+	return nil
+}
+
+// RequestCredential starts an OpenID4VCI flow that requests a credential from an issuer for the subject.
+// It returns the URL the user must be redirected to, to authorize the issuance.
+func (i Service) RequestCredential(ctx context.Context, subjectID string, request CredentialRequest) (*CredentialRequestResult, error) {
+	subject, err := i.getSubject(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := request.validate(subject.DIDs); err != nil {
+		return nil, err
+	}
+	request.ProfileID = ""
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := i.IAMClient.RequestOpenid4VCICredentialIssuanceWithBody(ctx, subjectID, "application/json", bytes.NewReader(requestBody))
+	response, err := nuts.ParseResponse(err, httpResponse, iam.ParseRequestOpenid4VCICredentialIssuanceResponse)
+	if err != nil {
+		return nil, err
+	}
+	return &CredentialRequestResult{RedirectURI: response.JSON200.RedirectUri}, nil
+}
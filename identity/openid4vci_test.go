@@ -0,0 +1,52 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialRequest_validate(t *testing.T) {
+	subjectDIDs := []string{"did:web:example.com:iam:1", "did:nuts:1"}
+	valid := CredentialRequest{
+		AuthorizationDetails: []map[string]interface{}{{"type": "openid_credential", "format": "ldp_vc"}},
+		Issuer:               "did:web:issuer.example.com",
+		WalletDID:            "did:web:example.com:iam:1",
+		RedirectURI:          "https://admin.example.com/#/admin/id/1",
+	}
+	t.Run("ok", func(t *testing.T) {
+		assert.NoError(t, valid.validate(subjectDIDs))
+	})
+	testCases := map[string]struct {
+		modify func(request *CredentialRequest)
+		err    string
+	}{
+		"missing authorization details": {
+			modify: func(request *CredentialRequest) { request.AuthorizationDetails = nil },
+			err:    "invalid credential request: authorization_details is required",
+		},
+		"missing issuer": {
+			modify: func(request *CredentialRequest) { request.Issuer = "" },
+			err:    "invalid credential request: issuer is required",
+		},
+		"DID of another subject": {
+			modify: func(request *CredentialRequest) { request.WalletDID = "did:web:example.com:iam:2" },
+			err:    "invalid credential request: wallet_did must be a DID of the subject",
+		},
+		"relative redirect URI": {
+			modify: func(request *CredentialRequest) { request.RedirectURI = "/#/admin/id/1" },
+			err:    "invalid credential request: redirect_uri must be an absolute URL",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			request := valid
+			testCase.modify(&request)
+
+			err := request.validate(subjectDIDs)
+
+			assert.ErrorIs(t, err, ErrInvalidCredentialRequest)
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+}
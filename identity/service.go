@@ -1,12 +1,19 @@
 package identity
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"slices"
 	"strings"
 
+	ssi "github.com/nuts-foundation/go-did"
+	"github.com/nuts-foundation/go-did/did"
+	"github.com/nuts-foundation/go-did/vc"
 	"github.com/nuts-foundation/go-nuts-client/nuts"
+	"github.com/nuts-foundation/go-nuts-client/nuts/iam"
 	"github.com/nuts-foundation/go-nuts-client/nuts/vcr"
 	"github.com/nuts-foundation/go-nuts-client/nuts/vdr"
 	"github.com/nuts-foundation/nuts-admin/discovery"
@@ -16,7 +23,10 @@ import (
 type Service struct {
 	VDRClient        *vdr.Client
 	VCRClient        *vcr.Client
+	IAMClient        *iam.Client
 	DiscoveryService discovery.Service
+	// ServiceProfiles contains the requirements for DID services of specific types.
+	ServiceProfiles []model.ServiceProfile
 }
 
 func (i Service) Create(ctx context.Context, subject *string) (*Identity, error) {
@@ -104,6 +114,103 @@ func (i Service) Get(ctx context.Context, subjectID string) (*IdentityDetails, e
 	return &result, nil
 }
 
+// Deactivate deactivates all DIDs of the given subject. This can't be undone.
+func (i Service) Deactivate(ctx context.Context, subjectID string) error {
+	httpResponse, err := i.VDRClient.Deactivate(ctx, subjectID)
+	_, err = nuts.ParseResponse(err, httpResponse, vdr.ParseDeactivateResponse)
+	return err
+}
+
+// AddVerificationMethod adds a new verification method to each DID of the subject.
+func (i Service) AddVerificationMethod(ctx context.Context, subjectID string) ([]did.VerificationMethod, error) {
+	httpResponse, err := i.VDRClient.AddVerificationMethod(ctx, subjectID)
+	response, err := nuts.ParseResponse(err, httpResponse, vdr.ParseAddVerificationMethodResponse)
+	if err != nil {
+		return nil, err
+	}
+	return *response.JSON200, nil
+}
+
+// ListServices returns the services of all DIDs of the subject.
+func (i Service) ListServices(ctx context.Context, subjectID string) ([]did.Service, error) {
+	httpResponse, err := i.VDRClient.FindServices(ctx, subjectID, &vdr.FindServicesParams{})
+	response, err := nuts.ParseResponse(err, httpResponse, vdr.ParseFindServicesResponse)
+	if err != nil {
+		return nil, err
+	}
+	return *response.JSON200, nil
+}
+
+// CreateService adds a service to each DID of the subject. It returns the services as added to the DID documents.
+// The service is validated against the service profile of its type, and may not already exist on one of the subject's DIDs.
+func (i Service) CreateService(ctx context.Context, subjectID string, service did.Service) ([]did.Service, error) {
+	existing, err := i.ListServices(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateService(i.ServiceProfiles, service, existing); err != nil {
+		return nil, err
+	}
+	httpResponse, err := i.VDRClient.CreateService(ctx, subjectID, vdr.CreateServiceJSONRequestBody(service))
+	response, err := nuts.ParseResponse(err, httpResponse, vdr.ParseCreateServiceResponse)
+	if err != nil {
+		return nil, err
+	}
+	return *response.JSON200, nil
+}
+
+// UpdateService replaces the service with the given ID on all DIDs of the subject.
+// The service ID may be a full DID URL or just its fragment.
+func (i Service) UpdateService(ctx context.Context, subjectID string, serviceID string, service did.Service) ([]did.Service, error) {
+	existing, err := i.ListServices(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	fragment := serviceID
+	if idx := strings.LastIndex(serviceID, "#"); idx >= 0 {
+		fragment = serviceID[idx+1:]
+	}
+	if !slices.ContainsFunc(existing, func(curr did.Service) bool { return curr.ID.Fragment == fragment }) {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+	}
+	toValidate := service
+	toValidate.ID = ssi.MustParseURI("#" + fragment)
+	if err := validateService(i.ServiceProfiles, toValidate, existing); err != nil {
+		return nil, err
+	}
+	httpResponse, err := i.VDRClient.UpdateService(ctx, subjectID, serviceID, vdr.UpdateServiceJSONRequestBody(service))
+	response, err := nuts.ParseResponse(err, httpResponse, vdr.ParseUpdateServiceResponse)
+	if err != nil {
+		return nil, err
+	}
+	return *response.JSON200, nil
+}
+
+// DeleteService removes the service with the given ID from the DIDs of the subject.
+func (i Service) DeleteService(ctx context.Context, subjectID string, serviceID string) error {
+	httpResponse, err := i.VDRClient.DeleteService(ctx, subjectID, serviceID)
+	_, err = nuts.ParseResponse(err, httpResponse, vdr.ParseDeleteServiceResponse)
+	return err
+}
+
+// LoadCredential loads the given Verifiable Credential into the wallet of the subject.
+func (i Service) LoadCredential(ctx context.Context, subjectID string, credential vc.VerifiableCredential) error {
+	requestBody, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+	httpResponse, err := i.VCRClient.LoadVCWithBody(ctx, subjectID, "application/json", bytes.NewReader(requestBody))
+	_, err = nuts.ParseResponse(err, httpResponse, vcr.ParseLoadVCResponse)
+	return err
+}
+
+// RemoveCredential removes the Verifiable Credential with the given ID from the wallet of the subject.
+func (i Service) RemoveCredential(ctx context.Context, subjectID string, credentialID string) error {
+	httpResponse, err := i.VCRClient.RemoveCredentialFromWallet(ctx, subjectID, credentialID)
+	_, err = nuts.ParseResponse(err, httpResponse, vcr.ParseRemoveCredentialFromWalletResponse)
+	return err
+}
+
 func (i Service) getSubject(ctx context.Context, subject string) (*Identity, error) {
 	httpResponse, err := i.VDRClient.SubjectDIDs(ctx, subject)
 	response, err := nuts.ParseResponse(err, httpResponse, vdr.ParseSubjectDIDsResponse)
@@ -116,6 +223,11 @@ func (i Service) getSubject(ctx context.Context, subject string) (*Identity, err
 	}, nil
 }
 
+// WalletCredentials returns the credentials in the wallet of the subject.
+func (i Service) WalletCredentials(ctx context.Context, subjectID string) ([]model.CredentialWithStatus, error) {
+	return i.credentialsInWallet(ctx, subjectID)
+}
+
 func (i Service) credentialsInWallet(ctx context.Context, subjectID string) ([]model.CredentialWithStatus, error) {
 	httpResponse, err := i.VCRClient.SearchCredentialsInWallet(ctx, subjectID)
 	response, err := nuts.ParseResponse(err, httpResponse, vcr.ParseSearchCredentialsInWalletResponse)
@@ -0,0 +1,106 @@
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nuts-foundation/go-did/did"
+	"github.com/nuts-foundation/nuts-admin/model"
+)
+
+// ErrInvalidService is returned when a DID service does not meet the requirements of its service profile.
+var ErrInvalidService = errors.New("invalid service")
+
+// ErrServiceNotFound is returned when a DID service does not exist on any of the subject's DIDs.
+var ErrServiceNotFound = errors.New("service not found")
+
+// validateService checks the service against the profile of its type (if any) and the services already registered for the subject.
+// Services with the same fragment as the given service are considered to be the service itself (e.g. when updating), and are not reported as duplicates.
+func validateService(profiles []model.ServiceProfile, service did.Service, existing []did.Service) error {
+	if strings.TrimSpace(service.Type) == "" {
+		return fmt.Errorf("%w: type is required", ErrInvalidService)
+	}
+	var profile *model.ServiceProfile
+	for _, curr := range profiles {
+		if curr.Type == service.Type {
+			profile = &curr
+			break
+		}
+	}
+	configured := profile != nil
+	if !configured {
+		// No profile for this type, only check the shape of the endpoint
+		profile = &model.ServiceProfile{Type: service.Type}
+	}
+
+	switch endpoint := service.ServiceEndpoint.(type) {
+	case string:
+		if profile.Compound {
+			return fmt.Errorf("%w: service endpoint of type %s must be an object", ErrInvalidService, service.Type)
+		}
+		if err := validateEndpointURL(endpoint, profile.AllowHTTP); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidService, err)
+		}
+	case map[string]interface{}:
+		if configured && !profile.Compound {
+			return fmt.Errorf("%w: service endpoint of type %s must be a URL", ErrInvalidService, service.Type)
+		}
+		if len(endpoint) == 0 {
+			return fmt.Errorf("%w: service endpoint must not be empty", ErrInvalidService)
+		}
+		for _, key := range profile.RequiredKeys {
+			if _, ok := endpoint[key]; !ok {
+				return fmt.Errorf("%w: service endpoint of type %s is missing required key: %s", ErrInvalidService, service.Type, key)
+			}
+		}
+		for key, value := range endpoint {
+			endpointURL, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%w: service endpoint key %s must be a URL", ErrInvalidService, key)
+			}
+			if err := validateEndpointURL(endpointURL, profile.AllowHTTP); err != nil {
+				return fmt.Errorf("%w: service endpoint key %s: %w", ErrInvalidService, key, err)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: service endpoint must be a URL or an object with URLs", ErrInvalidService)
+	}
+
+	// The Nuts node registers a service on all DIDs of the subject, so a service of the same type on any of them is a duplicate
+	var duplicates []string
+	for _, curr := range existing {
+		if curr.Type != service.Type {
+			continue
+		}
+		if service.ID.Fragment != "" && curr.ID.Fragment == service.ID.Fragment {
+			continue
+		}
+		duplicates = append(duplicates, curr.ID.String())
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%w: service of type %s already exists: %s", ErrInvalidService, service.Type, strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+func validateEndpointURL(endpoint string, allowHTTP bool) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "https" && !(allowHTTP && parsed.Scheme == "http") {
+		if allowHTTP {
+			return fmt.Errorf("URL must use http or https: %s", endpoint)
+		}
+		return fmt.Errorf("URL must use https: %s", endpoint)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("URL must contain a host: %s", endpoint)
+	}
+	if parsed.Fragment != "" {
+		return fmt.Errorf("URL must not contain a fragment: %s", endpoint)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package identity
+
+import (
+	"testing"
+
+	ssi "github.com/nuts-foundation/go-did"
+	"github.com/nuts-foundation/go-did/did"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateService(t *testing.T) {
+	profiles := []model.ServiceProfile{
+		{Type: "fhir"},
+		{Type: "eOverdracht-receiver", Compound: true, RequiredKeys: []string{"oauth", "notification"}},
+		{Type: "dev", AllowHTTP: true},
+	}
+	existing := []did.Service{
+		{ID: ssi.MustParseURI("did:web:example.com#oauth"), Type: "oauth", ServiceEndpoint: "https://example.com/oauth"},
+		{ID: ssi.MustParseURI("did:nuts:123#oauth"), Type: "oauth", ServiceEndpoint: "https://example.com/oauth"},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "fhir", ServiceEndpoint: "https://example.com/fhir"}, existing)
+		assert.NoError(t, err)
+	})
+	t.Run("ok - compound", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "eOverdracht-receiver", ServiceEndpoint: map[string]interface{}{
+			"oauth":        "https://example.com/oauth",
+			"notification": "https://example.com/notify",
+		}}, existing)
+		assert.NoError(t, err)
+	})
+	t.Run("ok - type without profile", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "other", ServiceEndpoint: map[string]interface{}{"a": "https://example.com"}}, existing)
+		assert.NoError(t, err)
+	})
+	t.Run("ok - http allowed by profile", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "dev", ServiceEndpoint: "http://localhost:8080"}, existing)
+		assert.NoError(t, err)
+	})
+	t.Run("ok - update of existing service", func(t *testing.T) {
+		err := validateService(profiles, did.Service{ID: ssi.MustParseURI("#oauth"), Type: "oauth", ServiceEndpoint: "https://example.com/new"}, existing)
+		assert.NoError(t, err)
+	})
+	t.Run("missing type", func(t *testing.T) {
+		err := validateService(profiles, did.Service{ServiceEndpoint: "https://example.com"}, existing)
+		assert.ErrorIs(t, err, ErrInvalidService)
+	})
+	t.Run("http not allowed", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "fhir", ServiceEndpoint: "http://example.com/fhir"}, existing)
+		assert.EqualError(t, err, "invalid service: URL must use https: http://example.com/fhir")
+	})
+	t.Run("URL without host", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "fhir", ServiceEndpoint: "https:///fhir"}, existing)
+		assert.EqualError(t, err, "invalid service: URL must contain a host: https:///fhir")
+	})
+	t.Run("compound endpoint for URL profile", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "fhir", ServiceEndpoint: map[string]interface{}{"a": "https://example.com"}}, existing)
+		assert.EqualError(t, err, "invalid service: service endpoint of type fhir must be a URL")
+	})
+	t.Run("URL for compound profile", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "eOverdracht-receiver", ServiceEndpoint: "https://example.com"}, existing)
+		assert.EqualError(t, err, "invalid service: service endpoint of type eOverdracht-receiver must be an object")
+	})
+	t.Run("missing required key", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "eOverdracht-receiver", ServiceEndpoint: map[string]interface{}{
+			"oauth": "https://example.com/oauth",
+		}}, existing)
+		assert.EqualError(t, err, "invalid service: service endpoint of type eOverdracht-receiver is missing required key: notification")
+	})
+	t.Run("compound endpoint with non-URL value", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "other", ServiceEndpoint: map[string]interface{}{"a": 1}}, existing)
+		assert.EqualError(t, err, "invalid service: service endpoint key a must be a URL")
+	})
+	t.Run("duplicate", func(t *testing.T) {
+		err := validateService(profiles, did.Service{Type: "oauth", ServiceEndpoint: "https://example.com/oauth"}, existing)
+		assert.EqualError(t, err, "invalid service: service of type oauth already exists: did:web:example.com#oauth, did:nuts:123#oauth")
+	})
+}
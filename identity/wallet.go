@@ -0,0 +1,425 @@
+package identity
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/go-nuts-client/nuts"
+	"github.com/nuts-foundation/go-nuts-client/nuts/vcr"
+	"github.com/nuts-foundation/nuts-admin/model"
+)
+
+// ErrInvalidBundle is returned when credentials to import can't be read.
+var ErrInvalidBundle = errors.New("invalid credential bundle")
+
+// ErrInvalidCredential is returned when a credential can't be loaded into a wallet, e.g. because it's expired or revoked.
+var ErrInvalidCredential = errors.New("invalid credential")
+
+// maxBundleEntrySize is the maximum decompressed size of a file in a ZIP bundle; credentials are much smaller.
+const maxBundleEntrySize = 1024 * 1024
+
+// maxBundleSize is the maximum decompressed size of all files in a ZIP bundle.
+// The upload size limit only applies to the compressed bundle.
+const maxBundleSize = 50 * 1024 * 1024
+
+// bundleManifestFile is the name of the file in a wallet bundle that describes the exported credentials.
+const bundleManifestFile = "manifest.json"
+
+// BundleManifest describes the credentials in a wallet bundle.
+type BundleManifest struct {
+	Subject     string        `json:"subject"`
+	DIDs        []string      `json:"dids"`
+	ExportedAt  time.Time     `json:"exported_at"`
+	Credentials []BundleEntry `json:"credentials"`
+}
+
+// BundleEntry describes a credential in a wallet bundle.
+type BundleEntry struct {
+	// File is the name of the file in the bundle that contains the credential.
+	File           string     `json:"file"`
+	ID             string     `json:"id,omitempty"`
+	Types          []string   `json:"types"`
+	Issuer         string     `json:"issuer"`
+	Format         string     `json:"format"`
+	Status         string     `json:"status"`
+	IssuanceDate   time.Time  `json:"issuance_date"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+}
+
+// Statuses of credentials in a WalletImportResult.
+const (
+	ImportStatusImported  = "imported"
+	ImportStatusDuplicate = "duplicate"
+	ImportStatusInvalid   = "invalid"
+	ImportStatusFailed    = "failed"
+)
+
+// WalletImportResult contains the outcome for every credential of an import.
+type WalletImportResult struct {
+	Credentials []ImportedCredential `json:"credentials"`
+}
+
+// ImportedCredential is the outcome of importing a single credential.
+type ImportedCredential struct {
+	// Source identifies the credential in the imported data, e.g. the file name in a bundle.
+	Source  string   `json:"source"`
+	ID      string   `json:"id,omitempty"`
+	Types   []string `json:"types,omitempty"`
+	Status  string   `json:"status"`
+	Message string   `json:"message,omitempty"`
+}
+
+// bundleEntry is a credential read from imported data, which still has to be parsed.
+type bundleEntry struct {
+	source string
+	raw    string
+}
+
+// ExportWallet writes all credentials in the wallet of the subject as ZIP bundle,
+// with a manifest describing the credentials and a file per credential in its original format.
+func (i Service) ExportWallet(ctx context.Context, subjectID string, writer io.Writer) error {
+	subject, err := i.getSubject(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+	credentials, err := i.credentialsInWallet(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+	return writeBundle(*subject, credentials, time.Now(), writer)
+}
+
+// UploadCredential loads a single JSON-LD or JWT credential into the wallet of the subject, after checking it:
+// the credential subject must be one of the subject's DIDs, it must not be expired and it must pass verification by the Nuts node.
+// If the credential is rejected, an error wrapping ErrInvalidCredential is returned.
+func (i Service) UploadCredential(ctx context.Context, subjectID string, raw string) (*vc.VerifiableCredential, error) {
+	subject, err := i.getSubject(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := vc.ParseVerifiableCredential(Unquote(strings.TrimSpace(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse credential: %w", ErrInvalidCredential, err)
+	}
+	if err := i.checkCredential(ctx, *subject, *credential); err != nil {
+		return nil, err
+	}
+	if err := i.LoadCredential(ctx, subjectID, *credential); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// ImportWallet loads the credentials in the given data into the wallet of the subject.
+// The data can be a bundle created by ExportWallet, a JSON-LD credential, a JSON array of credentials or JWT credentials (one per line).
+// Credentials that are already in the wallet are reported as duplicate, credentials that are expired or
+// fail verification by the Nuts node (proof, issuer and revocation status) are reported as invalid,
+// as well as credentials that were issued to another subject.
+func (i Service) ImportWallet(ctx context.Context, subjectID string, data []byte) (*WalletImportResult, error) {
+	entries, err := parseBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := i.getSubject(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	inWallet, err := i.credentialsInWallet(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, credential := range inWallet {
+		if credential.ID != nil {
+			existing[credential.ID.String()] = true
+		}
+	}
+	result := WalletImportResult{Credentials: make([]ImportedCredential, 0)}
+	for _, entry := range entries {
+		outcome := ImportedCredential{Source: entry.source}
+		credential, err := vc.ParseVerifiableCredential(entry.raw)
+		if err != nil {
+			outcome.Status = ImportStatusInvalid
+			outcome.Message = "unable to parse credential: " + err.Error()
+			result.Credentials = append(result.Credentials, outcome)
+			continue
+		}
+		outcome.Types = credentialTypes(*credential)
+		if credential.ID != nil {
+			outcome.ID = credential.ID.String()
+		}
+		if outcome.ID != "" && existing[outcome.ID] {
+			outcome.Status = ImportStatusDuplicate
+		} else {
+			if err := i.checkCredential(ctx, *subject, *credential); errors.Is(err, ErrInvalidCredential) {
+				outcome.Status = ImportStatusInvalid
+				outcome.Message = err.Error()
+			} else if err != nil {
+				outcome.Status = ImportStatusFailed
+				outcome.Message = err.Error()
+			} else if err := i.LoadCredential(ctx, subjectID, *credential); err != nil {
+				outcome.Status = ImportStatusFailed
+				outcome.Message = err.Error()
+			} else {
+				outcome.Status = ImportStatusImported
+				if outcome.ID != "" {
+					existing[outcome.ID] = true
+				}
+			}
+		}
+		result.Credentials = append(result.Credentials, outcome)
+	}
+	return &result, nil
+}
+
+// checkCredential checks whether the credential can be loaded into the wallet of the subject.
+func (i Service) checkCredential(ctx context.Context, subject Identity, credential vc.VerifiableCredential) error {
+	if err := checkCredentialHolder(subject, credential); err != nil {
+		return err
+	}
+	if err := checkCredentialValidity(credential, time.Now()); err != nil {
+		return err
+	}
+	return i.VerifyCredential(ctx, credential)
+}
+
+// checkCredentialHolder checks whether the credential was issued to one of the subject's DIDs.
+func checkCredentialHolder(subject Identity, credential vc.VerifiableCredential) error {
+	holderDID, err := credential.SubjectDID()
+	if err != nil {
+		return fmt.Errorf("%w: credential subject must have a single DID: %w", ErrInvalidCredential, err)
+	}
+	if !slices.Contains(subject.DIDs, holderDID.String()) {
+		return fmt.Errorf("%w: credential subject %s is not a DID of subject %s", ErrInvalidCredential, holderDID, subject.Subject)
+	}
+	return nil
+}
+
+// checkCredentialValidity checks whether the credential is valid at the given time.
+func checkCredentialValidity(credential vc.VerifiableCredential, at time.Time) error {
+	if credential.ExpirationDate != nil && !credential.ExpirationDate.After(at) {
+		return fmt.Errorf("%w: credential expired at %s", ErrInvalidCredential, credential.ExpirationDate.Format(time.RFC3339))
+	}
+	if credential.IssuanceDate.After(at) {
+		return fmt.Errorf("%w: credential is not valid before %s", ErrInvalidCredential, credential.IssuanceDate.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CredentialVerification is the result of verifying a credential using the Nuts node.
+type CredentialVerification struct {
+	// Valid indicates whether the Nuts node considers the credential valid (proof, validity period and revocation status).
+	Valid bool
+	// Message describes why the credential is invalid.
+	Message string
+	// Revoked indicates whether the credential is revoked. It's nil if that's unknown:
+	// the Nuts node stops verifying at the first problem, so a credential that's invalid for another reason may be revoked or not.
+	Revoked *bool
+}
+
+// revokedMessage is the message of the Nuts node's verifier for a revoked credential.
+const revokedMessage = "credential is revoked"
+
+// VerifyCredential verifies the proof, issuer and revocation status of the credential using the Nuts node.
+// Untrusted issuers are allowed, since trust is only configured for credentials issued by did:nuts DIDs.
+// If the credential is invalid, an error wrapping ErrInvalidCredential is returned.
+func (i Service) VerifyCredential(ctx context.Context, credential vc.VerifiableCredential) error {
+	verification, err := i.Verify(ctx, credential)
+	if err != nil {
+		return err
+	}
+	if !verification.Valid {
+		message := "verification failed"
+		if verification.Message != "" {
+			message += ": " + verification.Message
+		}
+		return fmt.Errorf("%w: %s", ErrInvalidCredential, message)
+	}
+	return nil
+}
+
+// Verify verifies the credential like VerifyCredential, but returns the result instead of an error if the credential is invalid.
+func (i Service) Verify(ctx context.Context, credential vc.VerifiableCredential) (*CredentialVerification, error) {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"verifiableCredential": credential,
+		"verificationOptions": map[string]interface{}{
+			"allowUntrustedIssuer": true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := i.VCRClient.VerifyVCWithBody(ctx, "application/json", bytes.NewReader(requestBody))
+	response, err := nuts.ParseResponse(err, httpResponse, vcr.ParseVerifyVCResponse)
+	if err != nil {
+		return nil, err
+	}
+	result := CredentialVerification{Valid: response.JSON200.Validity}
+	if response.JSON200.Message != nil {
+		result.Message = *response.JSON200.Message
+	}
+	if result.Valid || strings.Contains(result.Message, revokedMessage) {
+		revoked := !result.Valid
+		result.Revoked = &revoked
+	}
+	return &result, nil
+}
+
+func writeBundle(subject Identity, credentials []model.CredentialWithStatus, exportedAt time.Time, writer io.Writer) error {
+	manifest := BundleManifest{
+		Subject:     subject.Subject,
+		DIDs:        subject.DIDs,
+		ExportedAt:  exportedAt,
+		Credentials: make([]BundleEntry, 0),
+	}
+	archive := zip.NewWriter(writer)
+	for index, current := range credentials {
+		credential := vc.VerifiableCredential(current.VerifiableCredential)
+		entry := BundleEntry{
+			Types:          credentialTypes(credential),
+			Issuer:         credential.Issuer.String(),
+			Format:         credential.Format(),
+			Status:         current.Status,
+			IssuanceDate:   credential.IssuanceDate,
+			ExpirationDate: credential.ExpirationDate,
+		}
+		if credential.ID != nil {
+			entry.ID = credential.ID.String()
+		}
+		data := []byte(credential.Raw())
+		if credential.Format() == vc.JWTCredentialProofFormat {
+			entry.File = fmt.Sprintf("credentials/%03d.jwt", index+1)
+		} else {
+			entry.File = fmt.Sprintf("credentials/%03d.json", index+1)
+			if len(data) == 0 {
+				var err error
+				if data, err = json.Marshal(credential); err != nil {
+					return err
+				}
+			}
+		}
+		if err := writeBundleFile(archive, entry.File, data); err != nil {
+			return err
+		}
+		manifest.Credentials = append(manifest.Credentials, entry)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeBundleFile(archive, bundleManifestFile, data); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeBundleFile(archive *zip.Writer, name string, data []byte) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
+
+// parseBundle splits the imported data into credentials, without parsing the credentials themselves.
+func parseBundle(data []byte) ([]bundleEntry, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return parseZIPBundle(data)
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("%w: no credentials", ErrInvalidBundle)
+	}
+	switch trimmed[0] {
+	case '{':
+		return []bundleEntry{{source: "credential", raw: string(trimmed)}}, nil
+	case '[':
+		var credentials []json.RawMessage
+		if err := json.Unmarshal(trimmed, &credentials); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+		var result []bundleEntry
+		for index, credential := range credentials {
+			result = append(result, bundleEntry{source: fmt.Sprintf("credential %d", index+1), raw: Unquote(string(credential))})
+		}
+		return result, nil
+	case '"':
+		return []bundleEntry{{source: "credential", raw: Unquote(string(trimmed))}}, nil
+	}
+	// JWT credentials, one per line
+	var result []bundleEntry
+	for index, line := range strings.Split(string(trimmed), "\n") {
+		if raw := strings.TrimSpace(line); raw != "" {
+			result = append(result, bundleEntry{source: fmt.Sprintf("line %d", index+1), raw: raw})
+		}
+	}
+	return result, nil
+}
+
+// parseZIPBundle reads the credentials from a ZIP file: all files except the bundle manifest are considered credentials.
+func parseZIPBundle(data []byte) ([]bundleEntry, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	var result []bundleEntry
+	var totalSize int
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || path.Base(file.Name) == bundleManifestFile {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBundle, file.Name, err)
+		}
+		// The sizes in the ZIP headers can't be trusted, so the decompressed data itself is limited
+		contents, err := io.ReadAll(io.LimitReader(reader, maxBundleEntrySize+1))
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBundle, file.Name, err)
+		}
+		if len(contents) > maxBundleEntrySize {
+			return nil, fmt.Errorf("%w: %s: file exceeds %d bytes", ErrInvalidBundle, file.Name, maxBundleEntrySize)
+		}
+		totalSize += len(contents)
+		if totalSize > maxBundleSize {
+			return nil, fmt.Errorf("%w: bundle exceeds %d bytes", ErrInvalidBundle, maxBundleSize)
+		}
+		result = append(result, bundleEntry{source: file.Name, raw: string(bytes.TrimSpace(contents))})
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: no credentials", ErrInvalidBundle)
+	}
+	return result, nil
+}
+
+// Unquote returns the string value if the input is a JSON string (e.g. a JWT credential posted as JSON), or the input itself otherwise.
+func Unquote(input string) string {
+	var value string
+	if strings.HasPrefix(input, `"`) && json.Unmarshal([]byte(input), &value) == nil {
+		return strings.TrimSpace(value)
+	}
+	return input
+}
+
+// credentialTypes returns the types of the credential, except for the VerifiableCredential base type.
+func credentialTypes(credential vc.VerifiableCredential) []string {
+	var result []string
+	for _, credentialType := range credential.Type {
+		if credentialType != vc.VerifiableCredentialTypeV1URI() {
+			result = append(result, credentialType.String())
+		}
+	}
+	return result
+}
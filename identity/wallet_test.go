@@ -0,0 +1,202 @@
+package identity
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCredential = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1"],
+  "id": "did:web:example.com#1",
+  "type": ["VerifiableCredential", "NutsOrganizationCredential"],
+  "issuer": "did:web:example.com",
+  "issuanceDate": "2024-01-01T00:00:00Z",
+  "credentialSubject": {"id": "did:web:example.com:iam:holder"}
+}`
+
+func Test_parseBundle(t *testing.T) {
+	t.Run("JSON-LD credential", func(t *testing.T) {
+		entries, err := parseBundle([]byte(testCredential))
+
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "credential", entries[0].source)
+		assert.JSONEq(t, testCredential, entries[0].raw)
+	})
+	t.Run("JSON array", func(t *testing.T) {
+		entries, err := parseBundle([]byte(`[` + testCredential + `, "ey.jwt.credential"]`))
+
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "credential 2", entries[1].source)
+		assert.Equal(t, "ey.jwt.credential", entries[1].raw)
+	})
+	t.Run("JWT credentials", func(t *testing.T) {
+		entries, err := parseBundle([]byte("ey.jwt.1\n\n ey.jwt.2 \n"))
+
+		require.NoError(t, err)
+		assert.Equal(t, []bundleEntry{{source: "line 1", raw: "ey.jwt.1"}, {source: "line 3", raw: "ey.jwt.2"}}, entries)
+	})
+	t.Run("empty", func(t *testing.T) {
+		_, err := parseBundle([]byte(" \n"))
+
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+	})
+	t.Run("invalid JSON array", func(t *testing.T) {
+		_, err := parseBundle([]byte(`[{`))
+
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+	})
+	zipBundle := func(t *testing.T, files map[string][]byte) []byte {
+		buf := new(bytes.Buffer)
+		writer := zip.NewWriter(buf)
+		for name, contents := range files {
+			file, err := writer.Create(name)
+			require.NoError(t, err)
+			_, err = file.Write(contents)
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+		return buf.Bytes()
+	}
+	t.Run("ZIP bundle", func(t *testing.T) {
+		entries, err := parseBundle(zipBundle(t, map[string][]byte{
+			"manifest.json":      []byte(`{}`),
+			"credentials/1.json": []byte(testCredential),
+		}))
+
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "credentials/1.json", entries[0].source)
+	})
+	t.Run("ZIP bundle with oversized file", func(t *testing.T) {
+		_, err := parseBundle(zipBundle(t, map[string][]byte{
+			"credentials/1.json": bytes.Repeat([]byte(" "), maxBundleEntrySize+1),
+		}))
+
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+		assert.ErrorContains(t, err, "credentials/1.json: file exceeds")
+	})
+	t.Run("ZIP bundle exceeding the total size", func(t *testing.T) {
+		files := map[string][]byte{}
+		for i := 0; i <= maxBundleSize/maxBundleEntrySize; i++ {
+			files[fmt.Sprintf("credentials/%d.json", i)] = bytes.Repeat([]byte(" "), maxBundleEntrySize)
+		}
+
+		_, err := parseBundle(zipBundle(t, files))
+
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+		assert.ErrorContains(t, err, "bundle exceeds")
+	})
+}
+
+func TestUnquote(t *testing.T) {
+	assert.Equal(t, "a.b.c", Unquote(`"a.b.c"`))
+	assert.Equal(t, "a.b.c", Unquote(`a.b.c`))
+	assert.Equal(t, `{"a":1}`, Unquote(`{"a":1}`))
+}
+
+func Test_writeBundle(t *testing.T) {
+	credential, err := vc.ParseVerifiableCredential(testCredential)
+	require.NoError(t, err)
+	credentials := []model.CredentialWithStatus{
+		{VerifiableCredential: model.VerifiableCredential(*credential), Status: "active"},
+	}
+	exportedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	buf := new(bytes.Buffer)
+
+	err = writeBundle(Identity{Subject: "holder", DIDs: []string{"did:web:example.com:iam:holder"}}, credentials, exportedAt, buf)
+
+	require.NoError(t, err)
+	t.Run("manifest", func(t *testing.T) {
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		file, err := archive.Open(bundleManifestFile)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		var manifest BundleManifest
+		require.NoError(t, json.Unmarshal(data, &manifest))
+
+		assert.Equal(t, "holder", manifest.Subject)
+		assert.Equal(t, exportedAt, manifest.ExportedAt)
+		require.Len(t, manifest.Credentials, 1)
+		assert.Equal(t, BundleEntry{
+			File:         "credentials/001.json",
+			ID:           "did:web:example.com#1",
+			Types:        []string{"NutsOrganizationCredential"},
+			Issuer:       "did:web:example.com",
+			Format:       vc.JSONLDCredentialProofFormat,
+			Status:       "active",
+			IssuanceDate: credential.IssuanceDate,
+		}, manifest.Credentials[0])
+	})
+	t.Run("can be imported", func(t *testing.T) {
+		entries, err := parseBundle(buf.Bytes())
+
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "credentials/001.json", entries[0].source)
+		assert.Equal(t, testCredential, entries[0].raw)
+	})
+}
+
+func Test_checkCredentialHolder(t *testing.T) {
+	credential, err := vc.ParseVerifiableCredential(testCredential)
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		err := checkCredentialHolder(Identity{Subject: "holder", DIDs: []string{"did:web:example.com:iam:holder"}}, *credential)
+
+		assert.NoError(t, err)
+	})
+	t.Run("issued to another DID", func(t *testing.T) {
+		err := checkCredentialHolder(Identity{Subject: "other", DIDs: []string{"did:web:example.com:iam:other"}}, *credential)
+
+		assert.ErrorIs(t, err, ErrInvalidCredential)
+		assert.EqualError(t, err, "invalid credential: credential subject did:web:example.com:iam:holder is not a DID of subject other")
+	})
+	t.Run("no credential subject ID", func(t *testing.T) {
+		withoutID := *credential
+		withoutID.CredentialSubject = []map[string]interface{}{{"name": "Hospital X"}}
+
+		err := checkCredentialHolder(Identity{Subject: "holder", DIDs: []string{"did:web:example.com:iam:holder"}}, withoutID)
+
+		assert.ErrorIs(t, err, ErrInvalidCredential)
+		assert.ErrorContains(t, err, "credential subject must have a single DID")
+	})
+}
+
+func Test_checkCredentialValidity(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	expirationDate := now.Add(time.Hour)
+	credential := vc.VerifiableCredential{
+		IssuanceDate:   now.Add(-time.Hour),
+		ExpirationDate: &expirationDate,
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		assert.NoError(t, checkCredentialValidity(credential, now))
+	})
+	t.Run("expired", func(t *testing.T) {
+		err := checkCredentialValidity(credential, now.Add(2*time.Hour))
+
+		assert.ErrorIs(t, err, ErrInvalidCredential)
+		assert.EqualError(t, err, "invalid credential: credential expired at 2024-06-01T01:00:00Z")
+	})
+	t.Run("not yet valid", func(t *testing.T) {
+		err := checkCredentialValidity(credential, now.Add(-2*time.Hour))
+
+		assert.ErrorIs(t, err, ErrInvalidCredential)
+		assert.EqualError(t, err, "invalid credential: credential is not valid before 2024-05-31T23:00:00Z")
+	})
+}
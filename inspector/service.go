@@ -0,0 +1,280 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/go-nuts-client/nuts"
+	"github.com/nuts-foundation/go-nuts-client/nuts/vcr"
+	"github.com/nuts-foundation/nuts-admin/discovery"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/pe"
+)
+
+// ErrInvalidInput is returned when the input is not a Verifiable Presentation or Verifiable Credential.
+var ErrInvalidInput = errors.New("input is not a Verifiable Presentation or Verifiable Credential")
+
+// Kinds of inspected input.
+const (
+	KindPresentation = "presentation"
+	KindCredential   = "credential"
+)
+
+// Service decodes and verifies Verifiable Presentations and Verifiable Credentials, e.g. to debug discovery registrations.
+type Service struct {
+	VCRClient        *vcr.Client
+	IdentityService  identity.Service
+	DiscoveryService discovery.Service
+}
+
+// Report describes an inspected Verifiable Presentation or Verifiable Credential.
+type Report struct {
+	Kind   string `json:"kind"`
+	Format string `json:"format"`
+	// Decoded contains the JSON document, or for JWTs the decoded header and payload.
+	Decoded      interface{}         `json:"decoded"`
+	Presentation *PresentationReport `json:"presentation,omitempty"`
+	// Credentials contains the inspected credential, or the credentials in the presentation.
+	Credentials       []CredentialReport `json:"credentials"`
+	DiscoveryServices []DiscoveryMatch   `json:"discovery_services"`
+}
+
+// PresentationReport contains the verification result of a Verifiable Presentation.
+type PresentationReport struct {
+	ID             string     `json:"id,omitempty"`
+	Holder         string     `json:"holder,omitempty"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+	Expired        bool       `json:"expired"`
+	// Valid indicates whether the Nuts node considers the signature of the presentation valid.
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty"`
+}
+
+// CredentialReport contains the verification result of a Verifiable Credential.
+type CredentialReport struct {
+	ID             string     `json:"id,omitempty"`
+	Types          []string   `json:"types"`
+	Issuer         string     `json:"issuer"`
+	Subject        string     `json:"subject,omitempty"`
+	IssuanceDate   time.Time  `json:"issuance_date"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+	Expired        bool       `json:"expired"`
+	// Valid indicates whether the Nuts node considers the credential valid (signature, validity period and revocation status).
+	Valid bool `json:"valid"`
+	// Revoked indicates whether the credential is revoked, or is nil if that's unknown (see identity.CredentialVerification).
+	Revoked *bool `json:"revoked"`
+	// Trusted indicates whether the Nuts node trusts the issuer for one of the credential types.
+	Trusted bool   `json:"trusted"`
+	Message string `json:"message,omitempty"`
+}
+
+// DiscoveryMatch describes whether the credentials satisfy the presentation definition of a discovery service.
+type DiscoveryMatch struct {
+	ServiceID string `json:"service_id"`
+	pe.Evaluation
+	Error string `json:"error,omitempty"`
+}
+
+// Inspect decodes the given JWT or JSON-LD Verifiable Presentation or Verifiable Credential and verifies it using the Nuts node.
+// Issuer trust is reported separately, so credentials of untrusted issuers are verified as well.
+func (s Service) Inspect(ctx context.Context, raw string) (*Report, error) {
+	raw = identity.Unquote(strings.TrimSpace(raw))
+	decoded, isPresentation, err := decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	report := Report{Decoded: decoded, Credentials: make([]CredentialReport, 0)}
+	var credentials []vc.VerifiableCredential
+	if isPresentation {
+		presentation, err := vc.ParseVerifiablePresentation(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		report.Kind = KindPresentation
+		report.Format = presentation.Format()
+		if report.Presentation, err = s.inspectPresentation(ctx, *presentation); err != nil {
+			return nil, err
+		}
+		credentials = presentation.VerifiableCredential
+	} else {
+		credential, err := vc.ParseVerifiableCredential(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		report.Kind = KindCredential
+		report.Format = credential.Format()
+		credentials = []vc.VerifiableCredential{*credential}
+	}
+	trusted := map[string][]string{}
+	for _, credential := range credentials {
+		credentialReport, err := s.inspectCredential(ctx, credential, trusted)
+		if err != nil {
+			return nil, err
+		}
+		report.Credentials = append(report.Credentials, *credentialReport)
+	}
+	if report.DiscoveryServices, err = s.matchDiscoveryServices(ctx, credentials); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (s Service) inspectPresentation(ctx context.Context, presentation vc.VerifiablePresentation) (*PresentationReport, error) {
+	result := PresentationReport{}
+	if presentation.ID != nil {
+		result.ID = presentation.ID.String()
+	}
+	if presentation.Holder != nil {
+		result.Holder = presentation.Holder.String()
+	}
+	if token := presentation.JWT(); token != nil && !token.Expiration().IsZero() {
+		expirationDate := token.Expiration()
+		result.ExpirationDate = &expirationDate
+		result.Expired = !expirationDate.After(time.Now())
+	}
+	// Credentials are verified separately, so that untrusted issuers can be reported instead of failing verification.
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"verifiablePresentation": presentation,
+		"verifyCredentials":      false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := s.VCRClient.VerifyVPWithBody(ctx, "application/json", bytes.NewReader(requestBody))
+	response, err := nuts.ParseResponse(err, httpResponse, vcr.ParseVerifyVPResponse)
+	if err != nil {
+		return nil, err
+	}
+	result.Valid = response.JSON200.Validity
+	if response.JSON200.Message != nil {
+		result.Message = *response.JSON200.Message
+	}
+	return &result, nil
+}
+
+// inspectCredential verifies the credential. trusted caches the trusted issuers per credential type.
+func (s Service) inspectCredential(ctx context.Context, credential vc.VerifiableCredential, trusted map[string][]string) (*CredentialReport, error) {
+	result := CredentialReport{
+		Issuer:         credential.Issuer.String(),
+		IssuanceDate:   credential.IssuanceDate,
+		ExpirationDate: credential.ExpirationDate,
+		Expired:        credential.ExpirationDate != nil && !credential.ExpirationDate.After(time.Now()),
+	}
+	if credential.ID != nil {
+		result.ID = credential.ID.String()
+	}
+	if subjectDID, _ := credential.SubjectDID(); subjectDID != nil {
+		result.Subject = subjectDID.String()
+	}
+	for _, credentialType := range credential.Type {
+		if credentialType == vc.VerifiableCredentialTypeV1URI() {
+			continue
+		}
+		result.Types = append(result.Types, credentialType.String())
+		issuers, ok := trusted[credentialType.String()]
+		if !ok {
+			var err error
+			if issuers, err = s.trustedIssuers(ctx, credentialType.String()); err != nil {
+				return nil, err
+			}
+			trusted[credentialType.String()] = issuers
+		}
+		if slices.Contains(issuers, result.Issuer) {
+			result.Trusted = true
+		}
+	}
+	verification, err := s.IdentityService.Verify(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	result.Valid = verification.Valid
+	result.Revoked = verification.Revoked
+	result.Message = verification.Message
+	return &result, nil
+}
+
+func (s Service) trustedIssuers(ctx context.Context, credentialType string) ([]string, error) {
+	httpResponse, err := s.VCRClient.ListTrusted(ctx, credentialType)
+	response, err := nuts.ParseResponse(err, httpResponse, vcr.ParseListTrustedResponse)
+	if err != nil {
+		return nil, err
+	}
+	if response.JSON200 == nil {
+		return nil, nil
+	}
+	return *response.JSON200, nil
+}
+
+func (s Service) matchDiscoveryServices(ctx context.Context, credentials []vc.VerifiableCredential) ([]DiscoveryMatch, error) {
+	services, err := s.DiscoveryService.GetDiscoveryServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]DiscoveryMatch, 0)
+	for _, service := range services {
+		match := DiscoveryMatch{ServiceID: service.Id}
+		definition, err := pe.ParsePresentationDefinition(service.PresentationDefinition)
+		if err == nil {
+			var evaluation *pe.Evaluation
+			if evaluation, err = definition.Match(credentials); err == nil {
+				match.Evaluation = *evaluation
+			}
+		}
+		if err != nil {
+			match.Error = err.Error()
+		}
+		result = append(result, match)
+	}
+	return result, nil
+}
+
+// decode returns the input as generic JSON document (for JWTs the decoded header and payload),
+// and whether it's a Verifiable Presentation.
+func decode(raw string) (interface{}, bool, error) {
+	if strings.HasPrefix(raw, "{") {
+		var document map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &document); err != nil {
+			return nil, false, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		return document, hasType(document["type"], "VerifiablePresentation"), nil
+	}
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, false, fmt.Errorf("%w: not a JSON document or JWT", ErrInvalidInput)
+	}
+	var header, payload map[string]interface{}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, false, fmt.Errorf("%w: invalid JWT header: %w", ErrInvalidInput, err)
+	}
+	if err := decodeJWTPart(parts[1], &payload); err != nil {
+		return nil, false, fmt.Errorf("%w: invalid JWT payload: %w", ErrInvalidInput, err)
+	}
+	_, isPresentation := payload["vp"]
+	return map[string]interface{}{"header": header, "payload": payload}, isPresentation, nil
+}
+
+func decodeJWTPart(part string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func hasType(value interface{}, expected string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == expected
+	case []interface{}:
+		return slices.Contains(v, interface{}(expected))
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package inspector
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decode(t *testing.T) {
+	jwt := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+	}
+
+	t.Run("JSON-LD presentation", func(t *testing.T) {
+		decoded, isPresentation, err := decode(`{"type": ["VerifiablePresentation"]}`)
+
+		require.NoError(t, err)
+		assert.True(t, isPresentation)
+		assert.Equal(t, map[string]interface{}{"type": []interface{}{"VerifiablePresentation"}}, decoded)
+	})
+	t.Run("JSON-LD credential", func(t *testing.T) {
+		_, isPresentation, err := decode(`{"type": "VerifiableCredential"}`)
+
+		require.NoError(t, err)
+		assert.False(t, isPresentation)
+	})
+	t.Run("JWT presentation", func(t *testing.T) {
+		decoded, isPresentation, err := decode(jwt(`{"iss":"did:web:holder","vp":{}}`))
+
+		require.NoError(t, err)
+		assert.True(t, isPresentation)
+		assert.Equal(t, map[string]interface{}{"alg": "ES256"}, decoded.(map[string]interface{})["header"])
+		assert.Equal(t, "did:web:holder", decoded.(map[string]interface{})["payload"].(map[string]interface{})["iss"])
+	})
+	t.Run("JWT credential", func(t *testing.T) {
+		_, isPresentation, err := decode(jwt(`{"vc":{}}`))
+
+		require.NoError(t, err)
+		assert.False(t, isPresentation)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"{", "not a JWT", "a.b.c"} {
+			_, _, err := decode(input)
+
+			assert.ErrorIs(t, err, ErrInvalidInput, input)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package issuer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/job"
+	"github.com/nuts-foundation/nuts-admin/model"
+)
+
+// RevocationJobType is the type of the background job that revokes credentials in bulk.
+const RevocationJobType = "credential-revocation"
+
+// ErrInvalidRevocation is returned when a bulk revocation request is invalid.
+var ErrInvalidRevocation = errors.New("invalid revocation request")
+
+// CredentialFilter selects issued credentials. CredentialTypes is required, the other fields are optional.
+type CredentialFilter struct {
+	CredentialTypes []string `json:"credential_types"`
+	// Issuer limits the search to credentials issued by this DID.
+	Issuer string `json:"issuer,omitempty"`
+	// Holder limits the search to credentials issued to this DID (the credential subject).
+	Holder       string     `json:"holder,omitempty"`
+	IssuedAfter  *time.Time `json:"issued_after,omitempty"`
+	IssuedBefore *time.Time `json:"issued_before,omitempty"`
+}
+
+// matches returns whether the credential was issued within the time window of the filter.
+// The other criteria are applied by the Nuts node.
+func (f CredentialFilter) matches(credential model.CredentialWithStatus) bool {
+	if f.IssuedAfter != nil && credential.IssuanceDate.Before(*f.IssuedAfter) {
+		return false
+	}
+	if f.IssuedBefore != nil && !credential.IssuanceDate.Before(*f.IssuedBefore) {
+		return false
+	}
+	return true
+}
+
+// BulkRevokeRequest contains the parameters for revoking all issued credentials that match the filter.
+type BulkRevokeRequest struct {
+	CredentialFilter
+	// Reason is recorded for every revoked credential.
+	Reason string `json:"reason"`
+	// DryRun returns the credentials that would be revoked, without revoking them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// BulkRevokeResult contains the credentials selected for revocation and the job that revokes them.
+type BulkRevokeResult struct {
+	Credentials []model.CredentialWithStatus `json:"credentials"`
+	// Job is the background job that revokes the credentials. It's absent for a dry-run or if no credentials matched.
+	Job *job.Job `json:"job,omitempty"`
+}
+
+// RevocationRecord is nuts-admin's record of a revoked credential, since the Nuts node doesn't store a reason.
+type RevocationRecord struct {
+	CredentialID string    `json:"credential_id"`
+	Issuer       string    `json:"issuer"`
+	Holder       string    `json:"holder,omitempty"`
+	Types        []string  `json:"types"`
+	Reason       string    `json:"reason"`
+	RevokedAt    time.Time `json:"revoked_at"`
+}
+
+type revocationInput struct {
+	Reason      string             `json:"reason"`
+	Credentials []RevocationRecord `json:"credentials"`
+}
+
+// RegisterJobHandlers registers the handlers for the background jobs of this service.
+func (s Service) RegisterJobHandlers() {
+	s.Jobs.Register(RevocationJobType, s.runRevocation)
+}
+
+// SearchIssuedCredentials returns the credentials issued by local subjects that match the filter, newest first.
+func (s Service) SearchIssuedCredentials(ctx context.Context, filter CredentialFilter) ([]model.CredentialWithStatus, error) {
+	identities, err := s.IdentityService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var holder *string
+	if filter.Holder != "" {
+		holder = &filter.Holder
+	}
+	result := make([]model.CredentialWithStatus, 0)
+	for _, currID := range identities {
+		for _, issuerDID := range currID.DIDs {
+			if filter.Issuer != "" && filter.Issuer != issuerDID {
+				continue
+			}
+			credentials, err := s.searchIssued(ctx, issuerDID, filter.CredentialTypes, holder)
+			if err != nil {
+				return nil, err
+			}
+			for _, credential := range credentials {
+				if filter.matches(credential) {
+					result = append(result, credential)
+				}
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].IssuanceDate.After(result[j].IssuanceDate)
+	})
+	return result, nil
+}
+
+// BulkRevoke revokes all credentials that match the filter of the request and haven't been revoked yet.
+// The revocation is performed by a background job, which records every revoked credential with the given reason.
+func (s Service) BulkRevoke(ctx context.Context, request BulkRevokeRequest) (*BulkRevokeResult, error) {
+	request.Reason = strings.TrimSpace(request.Reason)
+	if request.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidRevocation)
+	}
+	if !slices.ContainsFunc(request.CredentialTypes, func(credentialType string) bool {
+		return strings.TrimSpace(credentialType) != ""
+	}) {
+		return nil, fmt.Errorf("%w: at least one credential type is required", ErrInvalidRevocation)
+	}
+	credentials, err := s.SearchIssuedCredentials(ctx, request.CredentialFilter)
+	if err != nil {
+		return nil, err
+	}
+	result := BulkRevokeResult{Credentials: make([]model.CredentialWithStatus, 0)}
+	input := revocationInput{Reason: request.Reason}
+	for _, credential := range credentials {
+		if credential.Status == "revoked" {
+			continue
+		}
+		result.Credentials = append(result.Credentials, credential)
+		input.Credentials = append(input.Credentials, revocationTarget(credential))
+	}
+	if request.DryRun || len(input.Credentials) == 0 {
+		return &result, nil
+	}
+	result.Job, err = s.Jobs.Start(ctx, RevocationJobType, len(input.Credentials), input)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Revocations returns the credentials revoked through nuts-admin, most recent first.
+func (s Service) Revocations() []RevocationRecord {
+	result := s.RevocationRecords.List()
+	slices.Reverse(result)
+	return result
+}
+
+func (s Service) runRevocation(ctx context.Context, data json.RawMessage, tracker job.Tracker) error {
+	var input revocationInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	for _, credential := range input.Credentials {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if tracker.Succeeded(credential.CredentialID) {
+			tracker.Report(job.Result{Item: credential.CredentialID, Status: job.ResultSkipped, Message: "revoked by previous attempt"})
+			continue
+		}
+		if err := s.Revoke(ctx, credential.CredentialID); err != nil {
+			tracker.Report(job.Result{Item: credential.CredentialID, Status: job.ResultFailed, Message: err.Error()})
+			continue
+		}
+		credential.Reason = input.Reason
+		credential.RevokedAt = time.Now()
+		if err := s.RevocationRecords.Add(credential); err != nil {
+			tracker.Report(job.Result{Item: credential.CredentialID, Status: job.ResultOK, Message: "revoked, but unable to record revocation: " + err.Error()})
+			continue
+		}
+		tracker.Report(job.Result{Item: credential.CredentialID, Status: job.ResultOK})
+	}
+	return nil
+}
+
+// revocationTarget returns the record of the credential, without the revocation details.
+func revocationTarget(credential model.CredentialWithStatus) RevocationRecord {
+	credentialVC := vc.VerifiableCredential(credential.VerifiableCredential)
+	record := RevocationRecord{
+		Issuer: credentialVC.Issuer.String(),
+	}
+	if credentialVC.ID != nil {
+		record.CredentialID = credentialVC.ID.String()
+	}
+	for _, credentialType := range credentialVC.Type {
+		if credentialType != vc.VerifiableCredentialTypeV1URI() {
+			record.Types = append(record.Types, credentialType.String())
+		}
+	}
+	if holderDID, _ := credentialVC.SubjectDID(); holderDID != nil {
+		record.Holder = holderDID.String()
+	}
+	return record
+}
@@ -0,0 +1,84 @@
+package issuer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ssi "github.com/nuts-foundation/go-did"
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialFilter_matches(t *testing.T) {
+	issuanceDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	credential := model.CredentialWithStatus{
+		VerifiableCredential: model.VerifiableCredential{IssuanceDate: issuanceDate},
+	}
+	before := issuanceDate.Add(-time.Hour)
+	after := issuanceDate.Add(time.Hour)
+
+	t.Run("no time window", func(t *testing.T) {
+		assert.True(t, CredentialFilter{}.matches(credential))
+	})
+	t.Run("within time window", func(t *testing.T) {
+		assert.True(t, CredentialFilter{IssuedAfter: &before, IssuedBefore: &after}.matches(credential))
+	})
+	t.Run("issued after is inclusive", func(t *testing.T) {
+		assert.True(t, CredentialFilter{IssuedAfter: &issuanceDate}.matches(credential))
+	})
+	t.Run("issued before is exclusive", func(t *testing.T) {
+		assert.False(t, CredentialFilter{IssuedBefore: &issuanceDate}.matches(credential))
+	})
+	t.Run("issued too early", func(t *testing.T) {
+		assert.False(t, CredentialFilter{IssuedAfter: &after}.matches(credential))
+	})
+	t.Run("issued too late", func(t *testing.T) {
+		assert.False(t, CredentialFilter{IssuedBefore: &before}.matches(credential))
+	})
+}
+
+func TestService_BulkRevoke(t *testing.T) {
+	t.Run("reason is required", func(t *testing.T) {
+		_, err := Service{}.BulkRevoke(context.Background(), BulkRevokeRequest{
+			CredentialFilter: CredentialFilter{CredentialTypes: []string{"NutsOrganizationCredential"}},
+			Reason:           " ",
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidRevocation)
+		assert.ErrorContains(t, err, "reason is required")
+	})
+	t.Run("credential type is required", func(t *testing.T) {
+		_, err := Service{}.BulkRevoke(context.Background(), BulkRevokeRequest{
+			CredentialFilter: CredentialFilter{CredentialTypes: []string{""}},
+			Reason:           "key compromise",
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidRevocation)
+		assert.ErrorContains(t, err, "at least one credential type is required")
+	})
+}
+
+func Test_revocationTarget(t *testing.T) {
+	credentialID := ssi.MustParseURI("did:web:example.com#1")
+	credential := model.CredentialWithStatus{
+		VerifiableCredential: model.VerifiableCredential{
+			ID:     &credentialID,
+			Type:   []ssi.URI{vc.VerifiableCredentialTypeV1URI(), ssi.MustParseURI("NutsOrganizationCredential")},
+			Issuer: ssi.MustParseURI("did:web:example.com"),
+			CredentialSubject: []map[string]interface{}{
+				{"id": "did:web:example.com:iam:holder"},
+			},
+		},
+	}
+
+	record := revocationTarget(credential)
+
+	assert.Equal(t, RevocationRecord{
+		CredentialID: "did:web:example.com#1",
+		Issuer:       "did:web:example.com",
+		Holder:       "did:web:example.com:iam:holder",
+		Types:        []string{"NutsOrganizationCredential"},
+	}, record)
+}
@@ -1,22 +1,121 @@
 package issuer
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
 
 	"github.com/nuts-foundation/go-nuts-client/nuts"
 	"github.com/nuts-foundation/go-nuts-client/nuts/vcr"
 	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/job"
 	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/nuts-foundation/nuts-admin/store"
 )
 
 type Service struct {
 	IdentityService identity.Service
 	VCRClient       *vcr.Client
+	Jobs            *job.Runner
+	// RevocationRecords contains the credentials revoked through nuts-admin.
+	RevocationRecords *store.Collection[RevocationRecord]
+}
+
+// IssueRequest contains the parameters for issuing a Verifiable Credential using the Nuts node.
+type IssueRequest struct {
+	Context                      Values                 `json:"@context,omitempty"`
+	Type                         Values                 `json:"type"`
+	Issuer                       string                 `json:"issuer"`
+	CredentialSubject            map[string]interface{} `json:"credentialSubject"`
+	ExpirationDate               *time.Time             `json:"expirationDate,omitempty"`
+	Format                       string                 `json:"format,omitempty"`
+	WithStatusList2021Revocation bool                   `json:"withStatusList2021Revocation,omitempty"`
+}
+
+// Values is a JSON property that holds either a single string or an array of strings, like @context and type.
+type Values []string
+
+// MarshalJSON writes a single value as a string and multiple values as an array.
+func (v Values) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
+
+// UnmarshalJSON accepts both a string and an array of strings.
+func (v *Values) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = Values{value}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(v))
+}
+
+// ErrInvalidIssueRequest is returned when a request to issue a credential is incomplete or invalid.
+var ErrInvalidIssueRequest = errors.New("invalid issue request")
+
+// validate checks the request before it's sent to the Nuts node.
+func (r IssueRequest) validate() error {
+	if len(r.Type) == 0 || slices.Contains(r.Type, "") {
+		return fmt.Errorf("%w: type is required", ErrInvalidIssueRequest)
+	}
+	if r.Issuer == "" {
+		return fmt.Errorf("%w: issuer is required", ErrInvalidIssueRequest)
+	}
+	if len(r.CredentialSubject) == 0 {
+		return fmt.Errorf("%w: credentialSubject is required", ErrInvalidIssueRequest)
+	}
+	if !slices.Contains([]string{"", vc.JSONLDCredentialProofFormat, vc.JWTCredentialProofFormat}, r.Format) {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidIssueRequest, r.Format)
+	}
+	if r.ExpirationDate != nil && !r.ExpirationDate.After(time.Now()) {
+		return fmt.Errorf("%w: expirationDate must be in the future", ErrInvalidIssueRequest)
+	}
+	return nil
+}
+
+// IssueCredential issues a Verifiable Credential. It does not load the credential into the holder's wallet.
+// If the request is invalid, an error wrapping ErrInvalidIssueRequest is returned.
+func (s Service) IssueCredential(ctx context.Context, request IssueRequest) (*vc.VerifiableCredential, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := s.VCRClient.IssueVCWithBody(ctx, "application/json", bytes.NewReader(requestBody))
+	response, err := nuts.ParseResponse(err, httpResponse, vcr.ParseIssueVCResponse)
+	if err != nil {
+		return nil, err
+	}
+	return response.JSON200, nil
 }
 
 func (s Service) GetIssuedCredentials(ctx context.Context, issuer string, credentialTypes []string) ([]model.CredentialWithStatus, error) {
+	result, err := s.searchIssued(ctx, issuer, credentialTypes, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Sort by issuance date, descending (newest first)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].VerifiableCredential.IssuanceDate.After(result[j].VerifiableCredential.IssuanceDate)
+	})
+	return result, nil
+}
+
+// searchIssued returns the credentials of the given types issued by the issuer DID, optionally only those issued to the given subject DID.
+func (s Service) searchIssued(ctx context.Context, issuer string, credentialTypes []string, subject *string) ([]model.CredentialWithStatus, error) {
 	var result []model.CredentialWithStatus
 	for _, credentialType := range credentialTypes {
 		credentialType = strings.TrimSpace(credentialType)
@@ -26,6 +125,7 @@ func (s Service) GetIssuedCredentials(ctx context.Context, issuer string, creden
 		httpResponse, err := s.VCRClient.SearchIssuedVCs(ctx, &vcr.SearchIssuedVCsParams{
 			CredentialType: credentialType,
 			Issuer:         issuer,
+			Subject:        subject,
 		})
 		response, err := nuts.ParseResponse(err, httpResponse, vcr.ParseSearchIssuedVCsResponse)
 		if err != nil {
@@ -35,9 +135,137 @@ func (s Service) GetIssuedCredentials(ctx context.Context, issuer string, creden
 			result = append(result, model.SearchResultToModel(searchResult))
 		}
 	}
-	// Sort by issuance date, descending (newest first)
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].VerifiableCredential.IssuanceDate.After(result[j].VerifiableCredential.IssuanceDate)
-	})
 	return result, nil
 }
+
+// GetCredential resolves the Verifiable Credential with the given ID.
+func (s Service) GetCredential(ctx context.Context, credentialID string) (*vc.VerifiableCredential, error) {
+	httpResponse, err := s.VCRClient.ResolveVC(ctx, credentialID)
+	response, err := nuts.ParseResponse(err, httpResponse, vcr.ParseResolveVCResponse)
+	if err != nil {
+		return nil, err
+	}
+	return response.JSON200, nil
+}
+
+// Revoke revokes the issued Verifiable Credential with the given ID.
+func (s Service) Revoke(ctx context.Context, credentialID string) error {
+	httpResponse, err := s.VCRClient.RevokeVC(ctx, credentialID)
+	_, err = nuts.ParseResponse(err, httpResponse, vcr.ParseRevokeVCResponse)
+	return err
+}
+
+// ErrNotRenewable is returned when a credential can't be renewed.
+var ErrNotRenewable = errors.New("credential can't be renewed")
+
+// RenewResult links a renewed credential to the credential it replaces.
+type RenewResult struct {
+	OriginalID string                  `json:"original_id"`
+	Renewed    vc.VerifiableCredential `json:"renewed"`
+	// Holder is the subject the renewed credential was loaded into, if the holder is a local subject.
+	Holder string `json:"holder,omitempty"`
+	// Revoked indicates whether the original credential was revoked.
+	Revoked bool `json:"revoked"`
+	// Errors contains the steps that failed after the credential was renewed, e.g. loading it into the wallet.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Renew re-issues an issued credential with the same subject claims and a new expiration date.
+// If expirationDate is nil, the renewed credential has the same validity period as the original.
+// If the holder is a local subject, the renewed credential is loaded into its wallet.
+// If revokeOriginal is true, the original credential is revoked after the renewed credential was issued.
+func (s Service) Renew(ctx context.Context, credentialID string, expirationDate *time.Time, revokeOriginal bool) (*RenewResult, error) {
+	original, err := s.GetCredential(ctx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := s.IdentityService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subjectOf(identities, original.Issuer.String()) == "" {
+		return nil, fmt.Errorf("%w: credential was not issued by a local subject", ErrNotRenewable)
+	}
+	request, err := renewRequest(*original, expirationDate)
+	if err != nil {
+		return nil, err
+	}
+	renewed, err := s.IssueCredential(ctx, *request)
+	if err != nil {
+		return nil, err
+	}
+
+	result := RenewResult{
+		OriginalID: credentialID,
+		Renewed:    *renewed,
+	}
+	if holderDID, _ := renewed.SubjectDID(); holderDID != nil {
+		if holder := subjectOf(identities, holderDID.String()); holder != "" {
+			if err := s.IdentityService.LoadCredential(ctx, holder, *renewed); err != nil {
+				result.Errors = append(result.Errors, "unable to load renewed credential into wallet: "+err.Error())
+			} else {
+				result.Holder = holder
+			}
+		}
+	}
+	if revokeOriginal {
+		if err := s.Revoke(ctx, credentialID); err != nil {
+			result.Errors = append(result.Errors, "unable to revoke original credential: "+err.Error())
+		} else {
+			result.Revoked = true
+		}
+	}
+	return &result, nil
+}
+
+// renewRequest creates the request to re-issue the given credential.
+func renewRequest(original vc.VerifiableCredential, expirationDate *time.Time) (*IssueRequest, error) {
+	if len(original.CredentialSubject) == 0 {
+		return nil, fmt.Errorf("%w: credential has no credential subject", ErrNotRenewable)
+	}
+	if len(original.CredentialSubject) != 1 {
+		return nil, fmt.Errorf("%w: credential must have exactly one credential subject", ErrNotRenewable)
+	}
+	request := IssueRequest{
+		Issuer:                       original.Issuer.String(),
+		CredentialSubject:            original.CredentialSubject[0],
+		Format:                       original.Format(),
+		WithStatusList2021Revocation: len(original.CredentialStatus) > 0,
+	}
+	for _, credentialContext := range original.Context {
+		if credentialContext != vc.VCContextV1URI() {
+			request.Context = append(request.Context, credentialContext.String())
+		}
+	}
+	for _, credentialType := range original.Type {
+		if credentialType != vc.VerifiableCredentialTypeV1URI() {
+			request.Type = append(request.Type, credentialType.String())
+		}
+	}
+	if len(request.Type) == 0 {
+		return nil, fmt.Errorf("%w: credential has no specific type", ErrNotRenewable)
+	}
+	if expirationDate == nil {
+		if original.ExpirationDate == nil {
+			return nil, fmt.Errorf("%w: expiration date is required, since the original credential doesn't expire", ErrNotRenewable)
+		}
+		validity := original.ExpirationDate.Sub(original.IssuanceDate)
+		renewedExpirationDate := time.Now().Add(validity)
+		expirationDate = &renewedExpirationDate
+	}
+	if !expirationDate.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiration date must be in the future", ErrNotRenewable)
+	}
+	request.ExpirationDate = expirationDate
+	return &request, nil
+}
+
+// subjectOf returns the subject that has the given DID, or an empty string if it isn't a local DID.
+func subjectOf(identities []identity.Identity, subjectDID string) string {
+	for _, current := range identities {
+		if slices.Contains(current.DIDs, subjectDID) {
+			return current.Subject
+		}
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package issuer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	ssi "github.com/nuts-foundation/go-did"
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_renewRequest(t *testing.T) {
+	issuanceDate := time.Now().Add(-300 * 24 * time.Hour)
+	expirationDate := issuanceDate.Add(365 * 24 * time.Hour)
+	original := vc.VerifiableCredential{
+		Context: []ssi.URI{vc.VCContextV1URI(), ssi.MustParseURI("https://nuts.nl/credentials/v1")},
+		Type:    []ssi.URI{vc.VerifiableCredentialTypeV1URI(), ssi.MustParseURI("NutsOrganizationCredential")},
+		Issuer:  ssi.MustParseURI("did:web:example.com"),
+		CredentialSubject: []map[string]interface{}{
+			{
+				"id":           "did:web:example.com:iam:holder",
+				"organization": map[string]interface{}{"name": "Hospital X"},
+			},
+		},
+		CredentialStatus: []interface{}{map[string]interface{}{"type": "StatusList2021Entry"}},
+		IssuanceDate:     issuanceDate,
+		ExpirationDate:   &expirationDate,
+	}
+
+	t.Run("same validity period", func(t *testing.T) {
+		request, err := renewRequest(original, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, Values{"https://nuts.nl/credentials/v1"}, request.Context)
+		assert.Equal(t, Values{"NutsOrganizationCredential"}, request.Type)
+		assert.Equal(t, "did:web:example.com", request.Issuer)
+		assert.Equal(t, original.CredentialSubject[0], request.CredentialSubject)
+		assert.True(t, request.WithStatusList2021Revocation)
+		assert.WithinDuration(t, time.Now().Add(365*24*time.Hour), *request.ExpirationDate, time.Minute)
+	})
+	t.Run("multiple contexts and types", func(t *testing.T) {
+		credential := original
+		credential.Context = append(credential.Context, ssi.MustParseURI("https://example.com/credentials/v1"))
+		credential.Type = append(credential.Type, ssi.MustParseURI("ExampleCredential"))
+
+		request, err := renewRequest(credential, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, Values{"https://nuts.nl/credentials/v1", "https://example.com/credentials/v1"}, request.Context)
+		assert.Equal(t, Values{"NutsOrganizationCredential", "ExampleCredential"}, request.Type)
+	})
+	t.Run("given expiration date", func(t *testing.T) {
+		newExpirationDate := time.Now().Add(24 * time.Hour)
+
+		request, err := renewRequest(original, &newExpirationDate)
+
+		require.NoError(t, err)
+		assert.Equal(t, newExpirationDate, *request.ExpirationDate)
+	})
+	t.Run("expiration date in the past", func(t *testing.T) {
+		newExpirationDate := time.Now().Add(-time.Hour)
+
+		_, err := renewRequest(original, &newExpirationDate)
+
+		assert.ErrorIs(t, err, ErrNotRenewable)
+	})
+	t.Run("original doesn't expire", func(t *testing.T) {
+		credential := original
+		credential.ExpirationDate = nil
+
+		_, err := renewRequest(credential, nil)
+
+		assert.EqualError(t, err, "credential can't be renewed: expiration date is required, since the original credential doesn't expire")
+	})
+	t.Run("multiple credential subjects", func(t *testing.T) {
+		credential := original
+		credential.CredentialSubject = append(credential.CredentialSubject, map[string]interface{}{})
+
+		_, err := renewRequest(credential, nil)
+
+		assert.ErrorIs(t, err, ErrNotRenewable)
+	})
+	t.Run("no credential subject", func(t *testing.T) {
+		credential := original
+		credential.CredentialSubject = nil
+
+		_, err := renewRequest(credential, nil)
+
+		assert.EqualError(t, err, "credential can't be renewed: credential has no credential subject")
+	})
+}
+
+func TestValues_JSON(t *testing.T) {
+	t.Run("single value", func(t *testing.T) {
+		var values Values
+		require.NoError(t, json.Unmarshal([]byte(`"NutsOrganizationCredential"`), &values))
+
+		assert.Equal(t, Values{"NutsOrganizationCredential"}, values)
+		data, _ := json.Marshal(values)
+		assert.JSONEq(t, `"NutsOrganizationCredential"`, string(data))
+	})
+	t.Run("multiple values", func(t *testing.T) {
+		var values Values
+		require.NoError(t, json.Unmarshal([]byte(`["NutsOrganizationCredential","ExampleCredential"]`), &values))
+
+		assert.Equal(t, Values{"NutsOrganizationCredential", "ExampleCredential"}, values)
+		data, _ := json.Marshal(values)
+		assert.JSONEq(t, `["NutsOrganizationCredential","ExampleCredential"]`, string(data))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var values Values
+		assert.Error(t, json.Unmarshal([]byte(`1`), &values))
+	})
+}
+
+func TestIssueRequest_validate(t *testing.T) {
+	expirationDate := time.Now().Add(time.Hour)
+	valid := IssueRequest{
+		Type:              Values{"NutsOrganizationCredential"},
+		Issuer:            "did:web:example.com",
+		CredentialSubject: map[string]interface{}{"id": "did:web:example.com:iam:holder"},
+		ExpirationDate:    &expirationDate,
+		Format:            vc.JWTCredentialProofFormat,
+	}
+	t.Run("ok", func(t *testing.T) {
+		assert.NoError(t, valid.validate())
+	})
+	testCases := map[string]struct {
+		modify func(request *IssueRequest)
+		err    string
+	}{
+		"missing type": {
+			modify: func(request *IssueRequest) { request.Type = nil },
+			err:    "invalid issue request: type is required",
+		},
+		"missing issuer": {
+			modify: func(request *IssueRequest) { request.Issuer = "" },
+			err:    "invalid issue request: issuer is required",
+		},
+		"missing credential subject": {
+			modify: func(request *IssueRequest) { request.CredentialSubject = nil },
+			err:    "invalid issue request: credentialSubject is required",
+		},
+		"unsupported format": {
+			modify: func(request *IssueRequest) { request.Format = "mso_mdoc" },
+			err:    `invalid issue request: unsupported format "mso_mdoc"`,
+		},
+		"expired": {
+			modify: func(request *IssueRequest) {
+				past := time.Now().Add(-time.Hour)
+				request.ExpirationDate = &past
+			},
+			err: "invalid issue request: expirationDate must be in the future",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			request := valid
+			testCase.modify(&request)
+
+			err := request.validate()
+
+			assert.ErrorIs(t, err, ErrInvalidIssueRequest)
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+}
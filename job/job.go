@@ -0,0 +1,51 @@
+package job
+
+import "time"
+
+// Status is the state of a job.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// ResultStatus is the outcome of processing a single item of a job.
+type ResultStatus string
+
+const (
+	ResultOK      ResultStatus = "ok"
+	ResultSkipped ResultStatus = "skipped"
+	ResultFailed  ResultStatus = "failed"
+)
+
+// Job is a long-running operation that processes a number of items in the background.
+type Job struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Status     Status     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// RetryOf contains the ID of the job this job retries, if it's a retry.
+	RetryOf string `json:"retry_of,omitempty"`
+	// Total is the number of items the job processes.
+	Total int `json:"total"`
+	// Results contains the outcome for each processed item, in order of processing.
+	Results []Result `json:"results"`
+	// Error contains the reason the job failed, if it didn't complete.
+	Error string `json:"error,omitempty"`
+}
+
+// Finished returns whether the job is no longer running.
+func (j Job) Finished() bool {
+	return j.Status != StatusRunning
+}
+
+// Result is the outcome of processing a single item of a job.
+type Result struct {
+	Item    string       `json:"item"`
+	Status  ResultStatus `json:"status"`
+	Message string       `json:"message,omitempty"`
+}
@@ -0,0 +1,297 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned when a job does not exist.
+var ErrNotFound = errors.New("job not found")
+
+// Handler performs the work of jobs of a specific type, given the JSON-encoded input the job was started with.
+// It reports the outcome of each processed item to the tracker, and should stop when the context is cancelled.
+// If it returns an error, the job is marked as failed.
+type Handler func(ctx context.Context, input json.RawMessage, tracker Tracker) error
+
+// Tracker records the progress of a running job.
+type Tracker interface {
+	// Report records the outcome of processing an item.
+	Report(result Result)
+	// Succeeded returns whether the item was processed successfully (or skipped) by the job this job retries.
+	// Handlers use it to skip those items when retrying a job.
+	Succeeded(item string) bool
+	// IsRetry returns whether the job is a retry of an earlier job.
+	IsRetry() bool
+}
+
+// Runner runs jobs in the background and keeps track of their progress.
+// If it's configured with a directory, the state of the jobs is persisted so that it survives restarts.
+type Runner struct {
+	dir      string
+	mux      sync.Mutex
+	handlers map[string]Handler
+	jobs     map[string]*entry
+}
+
+type entry struct {
+	Job
+	Input json.RawMessage `json:"input"`
+	// succeeded contains the items that were processed successfully by the job this job retries.
+	succeeded map[string]bool
+	cancel    context.CancelFunc
+}
+
+// NewRunner creates a Runner that persists jobs in the given directory. If dir is empty, jobs are only kept in memory.
+// Jobs that were still running when they were persisted (e.g. due to a restart) are marked as failed.
+func NewRunner(dir string) (*Runner, error) {
+	runner := &Runner{
+		dir:      dir,
+		handlers: map[string]Handler{},
+		jobs:     map[string]*entry{},
+	}
+	if dir == "" {
+		return runner, nil
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create job directory: %w", err)
+	}
+	if err := runner.load(); err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+// Register registers the handler for jobs of the given type.
+func (r *Runner) Register(jobType string, handler Handler) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.handlers[jobType] = handler
+}
+
+// Start starts a job of the given type in the background, which will process the given number of items.
+// The input is passed (JSON-encoded) to the handler of the job type. It returns the job as it was when started.
+// The handler gets the values of the given context (e.g. the user who started the job), but isn't cancelled with it.
+func (r *Runner) Start(ctx context.Context, jobType string, total int, input interface{}) (*Job, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	return r.start(ctx, jobType, total, data, nil)
+}
+
+// Retry starts a new job with the same type and input as the given job, which must be finished.
+// The handler can skip the items the given job processed successfully. As with Start, it gets the values of the given context.
+func (r *Runner) Retry(ctx context.Context, id string) (*Job, error) {
+	r.mux.Lock()
+	previous, ok := r.jobs[id]
+	if !ok {
+		r.mux.Unlock()
+		return nil, ErrNotFound
+	}
+	if !previous.Finished() {
+		r.mux.Unlock()
+		return nil, errors.New("job is still running")
+	}
+	succeeded := map[string]bool{}
+	for _, result := range previous.Results {
+		if result.Status != ResultFailed {
+			succeeded[result.Item] = true
+		}
+	}
+	jobType, total, input := previous.Type, previous.Total, previous.Input
+	r.mux.Unlock()
+	return r.start(ctx, jobType, total, input, &retryOf{id: id, succeeded: succeeded})
+}
+
+// Cancel requests the running job with the given ID to stop.
+func (r *Runner) Cancel(id string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	current, ok := r.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if current.Finished() || current.cancel == nil {
+		return errors.New("job is not running")
+	}
+	current.cancel()
+	return nil
+}
+
+// Get returns a snapshot of the job with the given ID.
+func (r *Runner) Get(id string) (*Job, bool) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	current, ok := r.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	result := copyJob(current.Job)
+	return &result, true
+}
+
+// List returns snapshots of all jobs, optionally only of the given type, newest first.
+func (r *Runner) List(jobType string) []Job {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	result := make([]Job, 0)
+	for _, current := range r.jobs {
+		if jobType == "" || current.Type == jobType {
+			result = append(result, copyJob(current.Job))
+		}
+	}
+	slices.SortFunc(result, func(a, b Job) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return result
+}
+
+type retryOf struct {
+	id        string
+	succeeded map[string]bool
+}
+
+func (r *Runner) start(parent context.Context, jobType string, total int, input json.RawMessage, retry *retryOf) (*Job, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	handler, ok := r.handlers[jobType]
+	if !ok {
+		return nil, fmt.Errorf("no handler for job type: %s", jobType)
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	current := &entry{
+		Job: Job{
+			ID:        uuid.NewString(),
+			Type:      jobType,
+			Status:    StatusRunning,
+			CreatedAt: time.Now(),
+			Total:     total,
+			Results:   make([]Result, 0),
+		},
+		Input:  input,
+		cancel: cancel,
+	}
+	if retry != nil {
+		current.RetryOf = retry.id
+		current.succeeded = retry.succeeded
+	}
+	r.jobs[current.ID] = current
+	r.persist(current)
+	started := copyJob(current.Job)
+
+	go r.run(ctx, current, handler)
+	return &started, nil
+}
+
+func (r *Runner) run(ctx context.Context, current *entry, handler Handler) {
+	var err error
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("job panicked: %v", recovered)
+			}
+		}()
+		err = handler(ctx, current.Input, tracker{runner: r, entry: current})
+	}()
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	now := time.Now()
+	current.FinishedAt = &now
+	switch {
+	case ctx.Err() != nil:
+		current.Status = StatusCancelled
+	case err != nil:
+		current.Status = StatusFailed
+		current.Error = err.Error()
+	default:
+		current.Status = StatusCompleted
+	}
+	current.cancel()
+	current.cancel = nil
+	r.persist(current)
+}
+
+// persist writes the job to disk. The caller must hold the lock.
+// Failures are not fatal for the job, so they're recorded in the job instead of returned.
+func (r *Runner) persist(current *entry) {
+	if r.dir == "" {
+		return
+	}
+	data, err := json.Marshal(current)
+	if err == nil {
+		target := filepath.Join(r.dir, current.ID+".json")
+		if err = os.WriteFile(target+".tmp", data, 0600); err == nil {
+			err = os.Rename(target+".tmp", target)
+		}
+	}
+	if err != nil && current.Error == "" {
+		current.Error = "unable to persist job: " + err.Error()
+	}
+}
+
+func (r *Runner) load() error {
+	files, err := os.ReadDir(r.dir)
+	if err != nil {
+		return fmt.Errorf("unable to read job directory: %w", err)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(r.dir, file.Name()))
+		if err != nil {
+			return fmt.Errorf("unable to read job: %w", err)
+		}
+		current := &entry{}
+		if err := json.Unmarshal(data, current); err != nil {
+			return fmt.Errorf("unable to parse job %s: %w", file.Name(), err)
+		}
+		if !current.Finished() {
+			// The process stopped while the job was running
+			current.Status = StatusFailed
+			current.Error = "job was interrupted"
+			finishedAt := time.Now()
+			current.FinishedAt = &finishedAt
+			r.persist(current)
+		}
+		r.jobs[current.ID] = current
+	}
+	return nil
+}
+
+type tracker struct {
+	runner *Runner
+	entry  *entry
+}
+
+func (t tracker) Report(result Result) {
+	t.runner.mux.Lock()
+	defer t.runner.mux.Unlock()
+	t.entry.Results = append(t.entry.Results, result)
+	t.runner.persist(t.entry)
+}
+
+func (t tracker) Succeeded(item string) bool {
+	return t.entry.succeeded[item]
+}
+
+func (t tracker) IsRetry() bool {
+	return t.entry.RetryOf != ""
+}
+
+func copyJob(job Job) Job {
+	result := job
+	result.Results = append(make([]Result, 0, len(job.Results)), job.Results...)
+	return result
+}
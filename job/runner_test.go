@@ -0,0 +1,159 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner(t *testing.T) {
+	waitFor := func(t *testing.T, runner *Runner, id string) *Job {
+		var job *Job
+		require.Eventually(t, func() bool {
+			job, _ = runner.Get(id)
+			return job.Finished()
+		}, time.Second, 10*time.Millisecond)
+		return job
+	}
+	// itemsHandler reports each item in the input as OK, except for items in the failing set
+	itemsHandler := func(failing map[string]bool) Handler {
+		return func(ctx context.Context, input json.RawMessage, tracker Tracker) error {
+			var items []string
+			if err := json.Unmarshal(input, &items); err != nil {
+				return err
+			}
+			for _, item := range items {
+				if tracker.Succeeded(item) {
+					tracker.Report(Result{Item: item, Status: ResultSkipped})
+				} else if failing[item] {
+					tracker.Report(Result{Item: item, Status: ResultFailed, Message: "oops"})
+				} else {
+					tracker.Report(Result{Item: item, Status: ResultOK})
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("completed", func(t *testing.T) {
+		runner, _ := NewRunner("")
+		runner.Register("test", itemsHandler(map[string]bool{"b": true}))
+
+		started, err := runner.Start(context.Background(), "test", 2, []string{"a", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, StatusRunning, started.Status)
+		job := waitFor(t, runner, started.ID)
+
+		assert.Equal(t, StatusCompleted, job.Status)
+		assert.Equal(t, 2, job.Total)
+		assert.Equal(t, []Result{{Item: "a", Status: ResultOK}, {Item: "b", Status: ResultFailed, Message: "oops"}}, job.Results)
+		assert.NotNil(t, job.FinishedAt)
+	})
+	t.Run("failed", func(t *testing.T) {
+		runner, _ := NewRunner("")
+		runner.Register("test", func(ctx context.Context, input json.RawMessage, tracker Tracker) error {
+			return errors.New("failed")
+		})
+
+		started, _ := runner.Start(context.Background(), "test", 1, nil)
+		job := waitFor(t, runner, started.ID)
+
+		assert.Equal(t, StatusFailed, job.Status)
+		assert.Equal(t, "failed", job.Error)
+	})
+	t.Run("panic", func(t *testing.T) {
+		runner, _ := NewRunner("")
+		runner.Register("test", func(ctx context.Context, input json.RawMessage, tracker Tracker) error {
+			panic("oops")
+		})
+
+		started, _ := runner.Start(context.Background(), "test", 1, nil)
+		job := waitFor(t, runner, started.ID)
+
+		assert.Equal(t, StatusFailed, job.Status)
+		assert.Equal(t, "job panicked: oops", job.Error)
+	})
+	t.Run("unknown job type", func(t *testing.T) {
+		runner, _ := NewRunner("")
+
+		_, err := runner.Start(context.Background(), "test", 1, nil)
+
+		assert.EqualError(t, err, "no handler for job type: test")
+	})
+	t.Run("unknown job", func(t *testing.T) {
+		runner, _ := NewRunner("")
+
+		_, ok := runner.Get("unknown")
+		assert.False(t, ok)
+		assert.ErrorIs(t, runner.Cancel("unknown"), ErrNotFound)
+		_, err := runner.Retry(context.Background(), "unknown")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		runner, _ := NewRunner("")
+		runner.Register("test", func(ctx context.Context, input json.RawMessage, tracker Tracker) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		started, _ := runner.Start(context.Background(), "test", 1, nil)
+
+		require.NoError(t, runner.Cancel(started.ID))
+		job := waitFor(t, runner, started.ID)
+
+		assert.Equal(t, StatusCancelled, job.Status)
+		assert.EqualError(t, runner.Cancel(started.ID), "job is not running")
+	})
+	t.Run("retry", func(t *testing.T) {
+		runner, _ := NewRunner("")
+		failing := map[string]bool{"b": true}
+		runner.Register("test", itemsHandler(failing))
+		started, _ := runner.Start(context.Background(), "test", 2, []string{"a", "b"})
+		waitFor(t, runner, started.ID)
+
+		delete(failing, "b")
+		retried, err := runner.Retry(context.Background(), started.ID)
+		require.NoError(t, err)
+		job := waitFor(t, runner, retried.ID)
+
+		assert.Equal(t, started.ID, job.RetryOf)
+		assert.Equal(t, []Result{{Item: "a", Status: ResultSkipped}, {Item: "b", Status: ResultOK}}, job.Results)
+	})
+	t.Run("persisted", func(t *testing.T) {
+		dir := t.TempDir()
+		runner, err := NewRunner(dir)
+		require.NoError(t, err)
+		runner.Register("test", itemsHandler(nil))
+		completed, _ := runner.Start(context.Background(), "test", 1, []string{"a"})
+		waitFor(t, runner, completed.ID)
+		blocking := make(chan struct{})
+		runner.Register("blocking", func(ctx context.Context, input json.RawMessage, tracker Tracker) error {
+			<-blocking
+			return nil
+		})
+		interrupted, _ := runner.Start(context.Background(), "blocking", 1, nil)
+
+		// Simulate restart
+		previous := runner
+		runner, err = NewRunner(dir)
+		require.NoError(t, err)
+		defer func() {
+			close(blocking)
+			waitFor(t, previous, interrupted.ID)
+		}()
+
+		jobs := runner.List("")
+		require.Len(t, jobs, 2)
+		job, _ := runner.Get(completed.ID)
+		assert.Equal(t, StatusCompleted, job.Status)
+		assert.Equal(t, []Result{{Item: "a", Status: ResultOK}}, job.Results)
+		job, _ = runner.Get(interrupted.ID)
+		assert.Equal(t, StatusFailed, job.Status)
+		assert.Equal(t, "job was interrupted", job.Error)
+		assert.Len(t, runner.List("blocking"), 1)
+	})
+}
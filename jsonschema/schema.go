@@ -0,0 +1,331 @@
+// Package jsonschema validates JSON documents against the subset of JSON Schema that nuts-admin needs to restrict request bodies
+// and to evaluate the filters of presentation definitions: type, enum, const, properties, required, additionalProperties, items,
+// contains, pattern and the length, size and range keywords.
+// Schemas using other keywords are rejected when they're compiled (see ErrUnsupportedKeyword), so they don't silently accept more than intended.
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// annotations are keywords that don't affect validation.
+var annotations = []string{"$schema", "$id", "$comment", "title", "description", "examples", "default", "format"}
+
+var types = []string{"string", "number", "integer", "boolean", "array", "object", "null"}
+
+// ErrUnsupportedKeyword is returned by Compile for keywords this package doesn't implement.
+// The schema may be valid, but it can't be evaluated.
+var ErrUnsupportedKeyword = errors.New("unsupported keyword")
+
+// SchemaError is returned by Compile when a keyword of the schema is invalid or unsupported.
+type SchemaError struct {
+	// Path is the location of the keyword in the schema, e.g. $.properties.name.pattern.
+	Path string
+	Err  error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("invalid schema: %s: %s", e.Path, e.Err)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	types                []string
+	enum                 []interface{}
+	constant             interface{}
+	hasConst             bool
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	items                *Schema
+	contains             *Schema
+	pattern              *regexp.Regexp
+	minLength, maxLength *int
+	minItems, maxItems   *int
+	minimum, maximum     *float64
+}
+
+// Compile compiles the schema, returning an error if it's invalid or uses unsupported keywords.
+func Compile(schema map[string]interface{}) (*Schema, error) {
+	return compile("$", schema)
+}
+
+func compile(path string, schema map[string]interface{}) (*Schema, error) {
+	result := &Schema{}
+	keywords := make([]string, 0, len(schema))
+	for keyword := range schema {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	for _, keyword := range keywords {
+		value := schema[keyword]
+		var err error
+		switch keyword {
+		case "type":
+			result.types, err = compileTypes(value)
+		case "enum":
+			var ok bool
+			if result.enum, ok = value.([]interface{}); !ok || len(result.enum) == 0 {
+				err = errors.New("must be a non-empty array")
+			}
+		case "const":
+			result.constant, result.hasConst = value, true
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				err = errors.New("must be an object")
+				break
+			}
+			result.properties = map[string]*Schema{}
+			for name, property := range properties {
+				if result.properties[name], err = compileSubschema(path+".properties."+name, property); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			result.required, err = compileStrings(value)
+		case "additionalProperties":
+			if allowed, ok := value.(bool); ok {
+				result.noAdditional = !allowed
+			} else if result.additionalProperties, err = compileSubschema(path+".additionalProperties", value); err != nil {
+				return nil, err
+			}
+		case "items":
+			if result.items, err = compileSubschema(path+".items", value); err != nil {
+				return nil, err
+			}
+		case "contains":
+			if result.contains, err = compileSubschema(path+".contains", value); err != nil {
+				return nil, err
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				err = errors.New("must be a string")
+			} else {
+				result.pattern, err = regexp.Compile(pattern)
+			}
+		case "minLength":
+			result.minLength, err = compileCount(value)
+		case "maxLength":
+			result.maxLength, err = compileCount(value)
+		case "minItems":
+			result.minItems, err = compileCount(value)
+		case "maxItems":
+			result.maxItems, err = compileCount(value)
+		case "minimum":
+			result.minimum, err = compileNumber(value)
+		case "maximum":
+			result.maximum, err = compileNumber(value)
+		default:
+			if !slices.Contains(annotations, keyword) {
+				err = ErrUnsupportedKeyword
+			}
+		}
+		if err != nil {
+			return nil, &SchemaError{Path: path + "." + keyword, Err: err}
+		}
+	}
+	return result, result.checkSatisfiable(path)
+}
+
+// checkSatisfiable rejects a const or enum option that doesn't have the required type, since nothing would match the schema.
+func (s *Schema) checkSatisfiable(path string) error {
+	if len(s.types) == 0 {
+		return nil
+	}
+	hasTypes := func(value interface{}) bool {
+		return slices.ContainsFunc(s.types, func(expected string) bool { return hasType(normalize(value), expected) })
+	}
+	typeNames := strings.Join(s.types, " or ")
+	if s.hasConst && !hasTypes(s.constant) {
+		return &SchemaError{Path: path + ".const", Err: fmt.Errorf("doesn't match type %s, so nothing matches the schema", typeNames)}
+	}
+	for i, option := range s.enum {
+		if !hasTypes(option) {
+			return &SchemaError{Path: fmt.Sprintf("%s.enum[%d]", path, i), Err: fmt.Errorf("doesn't match type %s", typeNames)}
+		}
+	}
+	return nil
+}
+
+func compileSubschema(path string, value interface{}) (*Schema, error) {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, &SchemaError{Path: path, Err: errors.New("must be an object")}
+	}
+	return compile(path, schema)
+}
+
+func compileTypes(value interface{}) ([]string, error) {
+	var result []string
+	if str, ok := value.(string); ok {
+		result = []string{str}
+	} else {
+		var err error
+		if result, err = compileStrings(value); err != nil {
+			return nil, err
+		}
+	}
+	for _, current := range result {
+		if !slices.Contains(types, current) {
+			return nil, fmt.Errorf("unknown type %q", current)
+		}
+	}
+	return result, nil
+}
+
+func compileStrings(value interface{}) ([]string, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("must be an array of strings")
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, errors.New("must be an array of strings")
+		}
+		result = append(result, str)
+	}
+	return result, nil
+}
+
+func compileCount(value interface{}) (*int, error) {
+	number, err := compileNumber(value)
+	if err != nil || *number < 0 || *number != math.Trunc(*number) {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	result := int(*number)
+	return &result, nil
+}
+
+func compileNumber(value interface{}) (*float64, error) {
+	switch number := value.(type) {
+	case float64:
+		return &number, nil
+	case int:
+		result := float64(number)
+		return &result, nil
+	}
+	return nil, errors.New("must be a number")
+}
+
+// Validate returns an error describing the first violation of the schema, if the document doesn't match it.
+// The document must be decoded using encoding/json into interface{}.
+func (s *Schema) Validate(document interface{}) error {
+	return s.validate("$", document)
+}
+
+func (s *Schema) validate(path string, value interface{}) error {
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(expected string) bool { return hasType(value, expected) }) {
+		return fmt.Errorf("%s: must be of type %s", path, strings.Join(s.types, " or "))
+	}
+	if s.hasConst && !reflect.DeepEqual(normalize(s.constant), value) {
+		return fmt.Errorf("%s: must be equal to %v", path, s.constant)
+	}
+	if len(s.enum) > 0 && !slices.ContainsFunc(s.enum, func(option interface{}) bool { return reflect.DeepEqual(normalize(option), value) }) {
+		return fmt.Errorf("%s: must be one of %v", path, s.enum)
+	}
+	switch v := value.(type) {
+	case string:
+		length := len([]rune(v))
+		if s.minLength != nil && length < *s.minLength {
+			return fmt.Errorf("%s: must be at least %d characters", path, *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			return fmt.Errorf("%s: must be at most %d characters", path, *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			return fmt.Errorf("%s: must match %s", path, s.pattern)
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			return fmt.Errorf("%s: must be at least %v", path, *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			return fmt.Errorf("%s: must be at most %v", path, *s.maximum)
+		}
+	case []interface{}:
+		if s.minItems != nil && len(v) < *s.minItems {
+			return fmt.Errorf("%s: must contain at least %d items", path, *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			return fmt.Errorf("%s: must contain at most %d items", path, *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range v {
+				if err := s.items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+		if s.contains != nil && !slices.ContainsFunc(v, func(item interface{}) bool { return s.contains.Validate(item) == nil }) {
+			return fmt.Errorf("%s: must contain an item that matches the schema", path)
+		}
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s.%s: is required", path, name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertySchema, ok := s.properties[name]
+			if !ok {
+				if s.noAdditional {
+					return fmt.Errorf("%s.%s: is not allowed", path, name)
+				}
+				propertySchema = s.additionalProperties
+			}
+			if propertySchema != nil {
+				if err := propertySchema.validate(path+"."+name, v[name]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasType(value interface{}, expected string) bool {
+	switch v := value.(type) {
+	case string:
+		return expected == "string"
+	case float64:
+		return expected == "number" || (expected == "integer" && v == math.Trunc(v))
+	case bool:
+		return expected == "boolean"
+	case []interface{}:
+		return expected == "array"
+	case map[string]interface{}:
+		return expected == "object"
+	case nil:
+		return expected == "null"
+	}
+	return false
+}
+
+// normalize converts numbers in schemas loaded from YAML to float64, as encoding/json decodes them.
+func normalize(value interface{}) interface{} {
+	if number, ok := value.(int); ok {
+		return float64(number)
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := Compile(map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"required":             []interface{}{"type", "credentialSubject"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"type":   map[string]interface{}{"enum": []interface{}{"NutsOrganizationCredential", "NutsUraCredential"}},
+			"issuer": map[string]interface{}{"type": "string", "pattern": "^did:web:"},
+			"credentialSubject": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"organization": map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": map[string]interface{}{"type": "string", "minLength": 1, "maxLength": 5},
+					},
+				},
+			},
+			"tags":    map[string]interface{}{"type": "array", "maxItems": 1, "items": map[string]interface{}{"type": "integer", "minimum": 0}},
+			"roles":   map[string]interface{}{"contains": map[string]interface{}{"const": "admin"}},
+			"version": map[string]interface{}{"const": 2},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		document string
+		err      string
+	}{
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {"organization": {"name": "X"}}, "tags": [1], "version": 2}`},
+		{document: `[]`, err: "$: must be of type object"},
+		{document: `{"type": "NutsUraCredential"}`, err: "$.credentialSubject: is required"},
+		{document: `{"type": "Other", "credentialSubject": {}}`, err: "$.type: must be one of [NutsOrganizationCredential NutsUraCredential]"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "other": true}`, err: "$.other: is not allowed"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "issuer": "did:x"}`, err: "$.issuer: must match ^did:web:"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {"organization": {"name": ""}}}`, err: "$.credentialSubject.organization.name: must be at least 1 characters"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {"organization": {"name": 1}}}`, err: "$.credentialSubject.organization.name: must be of type string"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "tags": [1.5]}`, err: "$.tags[0]: must be of type integer"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "tags": [-1]}`, err: "$.tags[0]: must be at least 0"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "tags": [1, 2]}`, err: "$.tags: must contain at most 1 items"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "version": 1}`, err: "$.version: must be equal to 2"},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "roles": ["user", "admin"]}`},
+		{document: `{"type": "NutsUraCredential", "credentialSubject": {}, "roles": ["user"]}`, err: "$.roles: must contain an item that matches the schema"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.document, func(t *testing.T) {
+			var document interface{}
+			require.NoError(t, json.Unmarshal([]byte(testCase.document), &document))
+
+			err := schema.Validate(document)
+
+			if testCase.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.err)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	testCases := map[string]struct {
+		schema map[string]interface{}
+		err    string
+	}{
+		"unsupported keyword": {
+			schema: map[string]interface{}{"oneOf": []interface{}{}},
+			err:    "invalid schema: $.oneOf: unsupported keyword",
+		},
+		"unknown type": {
+			schema: map[string]interface{}{"type": "text"},
+			err:    `invalid schema: $.type: unknown type "text"`,
+		},
+		"invalid pattern": {
+			schema: map[string]interface{}{"properties": map[string]interface{}{"name": map[string]interface{}{"pattern": "("}}},
+			err:    "invalid schema: $.properties.name.pattern: error parsing regexp: missing closing ): `(`",
+		},
+		"const doesn't match type": {
+			schema: map[string]interface{}{"type": "string", "const": 1},
+			err:    "invalid schema: $.const: doesn't match type string, so nothing matches the schema",
+		},
+		"enum option doesn't match type": {
+			schema: map[string]interface{}{"type": []interface{}{"string", "null"}, "enum": []interface{}{"a", nil, true}},
+			err:    "invalid schema: $.enum[2]: doesn't match type string or null",
+		},
+		"negative length": {
+			schema: map[string]interface{}{"minLength": -1},
+			err:    "invalid schema: $.minLength: must be a non-negative integer",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(testCase.schema)
+
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+	t.Run("unsupported keyword can be told apart", func(t *testing.T) {
+		_, err := Compile(map[string]interface{}{"items": map[string]interface{}{"oneOf": []interface{}{}}})
+
+		assert.ErrorIs(t, err, ErrUnsupportedKeyword)
+		var schemaErr *SchemaError
+		require.ErrorAs(t, err, &schemaErr)
+		assert.Equal(t, "$.items.oneOf", schemaErr.Path)
+	})
+	t.Run("null const", func(t *testing.T) {
+		schema, err := Compile(map[string]interface{}{"const": nil})
+		require.NoError(t, err)
+
+		assert.NoError(t, schema.Validate(nil))
+		assert.Error(t, schema.Validate("x"))
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nuts-foundation/nuts-admin/lint"
+)
+
+// runLint lints the discovery service definitions and policy files at the given paths (files or directories),
+// and returns the exit code: 0 if all files are valid, 1 if any file contains errors, 2 if the files can't be read.
+func runLint(out io.Writer, paths []string) int {
+	if len(paths) == 0 {
+		_, _ = fmt.Fprintln(out, "usage: nuts-admin lint <file or directory>...")
+		return 2
+	}
+	files, err := lint.ReadFiles(paths)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "unable to read files: %s\n", err)
+		return 2
+	}
+	report := lint.Lint(files)
+	for _, file := range report.Files {
+		if len(file.Issues) == 0 {
+			_, _ = fmt.Fprintf(out, "%s (%s): OK\n", file.Name, file.Kind)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "%s (%s):\n", file.Name, file.Kind)
+		for _, issue := range file.Issues {
+			_, _ = fmt.Fprintf(out, "  %s\n", issue)
+		}
+	}
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
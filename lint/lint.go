@@ -0,0 +1,314 @@
+// Package lint checks discovery service definitions and policy files before they're given to a Nuts node,
+// so mistakes are found without waiting for the node to refuse them.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of file that is linted.
+type Kind string
+
+const (
+	// KindDiscovery is a discovery service definition, as read by the Nuts node from discovery.definitions.directory.
+	KindDiscovery Kind = "discovery"
+	// KindPolicy is a policy file mapping scopes to presentation definitions, as read by the Nuts node from policy.directory.
+	KindPolicy Kind = "policy"
+	// KindUnknown is a file that is neither a discovery service definition nor a policy file.
+	KindUnknown Kind = "unknown"
+)
+
+// Severity indicates whether an issue makes the file invalid.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// File is a discovery service definition or policy file to lint.
+type File struct {
+	Name    string          `json:"name"`
+	Content json.RawMessage `json:"content"`
+}
+
+// Issue is a problem found in a file.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Path is the location of the problem in the file, as JSONPath.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String formats the issue for the command line.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Path, i.Message)
+}
+
+// FileReport contains the issues found in a file.
+type FileReport struct {
+	Name   string  `json:"name"`
+	Kind   Kind    `json:"kind"`
+	Issues []Issue `json:"issues"`
+}
+
+// Report contains the issues found in all linted files.
+type Report struct {
+	// Valid indicates none of the files contain errors. Files may still contain warnings.
+	Valid bool         `json:"valid"`
+	Files []FileReport `json:"files"`
+}
+
+// ReadFiles reads the JSON files at the given paths. Directories are read non-recursively, like the Nuts node does.
+func ReadFiles(paths []string) ([]File, error) {
+	var result []File
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		names := []string{path}
+		if info.IsDir() {
+			names, err = filepath.Glob(filepath.Join(path, "*.json"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range names {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, File{Name: name, Content: data})
+		}
+	}
+	return result, nil
+}
+
+// Lint checks the files, and the consistency of the presentation definition IDs used across files.
+func Lint(files []File) Report {
+	result := Report{Valid: true, Files: make([]FileReport, 0, len(files))}
+	var definitions []definitionUsage
+	for i, file := range files {
+		report, usages := lintFile(file)
+		for _, usage := range usages {
+			usage.file = i
+			usage.fileName = file.Name
+			definitions = append(definitions, usage)
+		}
+		result.Files = append(result.Files, report)
+	}
+	for _, issue := range checkDefinitionIDs(definitions) {
+		result.Files[issue.file].Issues = append(result.Files[issue.file].Issues, issue.Issue)
+	}
+	for _, file := range result.Files {
+		for _, issue := range file.Issues {
+			if issue.Severity == SeverityError {
+				result.Valid = false
+			}
+		}
+	}
+	return result
+}
+
+// definitionUsage is a presentation definition in a linted file, used to check the IDs across files.
+type definitionUsage struct {
+	file       int
+	fileName   string
+	path       string
+	id         string
+	definition map[string]interface{}
+}
+
+func lintFile(file File) (FileReport, []definitionUsage) {
+	result := FileReport{Name: file.Name, Kind: KindUnknown, Issues: make([]Issue, 0)}
+	var document map[string]interface{}
+	if err := json.Unmarshal(file.Content, &document); err != nil {
+		result.Issues = append(result.Issues, Issue{Severity: SeverityError, Path: "$", Message: fmt.Sprintf("invalid JSON object: %s", err)})
+		return result, nil
+	}
+	var definitions map[string]map[string]interface{}
+	var c checker
+	switch {
+	case document["presentation_definition"] != nil:
+		result.Kind = KindDiscovery
+		definitions = c.discoveryDefinition(document)
+	case len(document) > 0:
+		result.Kind = KindPolicy
+		definitions = c.policy(document)
+	default:
+		c.error("$", "file is neither a discovery service definition nor a policy file")
+	}
+	result.Issues = append(result.Issues, c.issues...)
+	var usages []definitionUsage
+	for path, definition := range definitions {
+		if id, ok := definition["id"].(string); ok && id != "" {
+			usages = append(usages, definitionUsage{path: path, id: id, definition: definition})
+		}
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].path < usages[j].path
+	})
+	return result, usages
+}
+
+type fileIssue struct {
+	Issue
+	file int
+}
+
+// checkDefinitionIDs reports presentation definitions that share an ID, but differ.
+// The Nuts node refers to presentation definitions by ID, so different definitions with the same ID are ambiguous.
+func checkDefinitionIDs(usages []definitionUsage) []fileIssue {
+	var result []fileIssue
+	first := map[string]definitionUsage{}
+	for _, usage := range usages {
+		previous, exists := first[usage.id]
+		if !exists {
+			first[usage.id] = usage
+			continue
+		}
+		if !reflect.DeepEqual(previous.definition, usage.definition) {
+			result = append(result, fileIssue{file: usage.file, Issue: Issue{
+				Severity: SeverityError,
+				Path:     usage.path,
+				Message:  fmt.Sprintf("presentation definition ID %q is also used by a different presentation definition (%s: %s)", usage.id, previous.fileName, previous.path),
+			}})
+		}
+	}
+	return result
+}
+
+// checker collects the issues found while checking a file.
+type checker struct {
+	issues []Issue
+}
+
+func (c *checker) error(path string, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{Severity: SeverityError, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) warning(path string, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{Severity: SeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// discoveryDefinition checks a discovery service definition, and returns its presentation definition by path.
+func (c *checker) discoveryDefinition(document map[string]interface{}) map[string]map[string]interface{} {
+	c.unknownProperties("$", document, "id", "endpoint", "presentation_max_validity", "presentation_definition")
+	id := c.requiredString("$", document, "id")
+	if endpoint := c.requiredString("$", document, "endpoint"); endpoint != "" && !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
+		c.error("$.endpoint", "must be an HTTP(S) URL")
+	}
+	if validity, ok := document["presentation_max_validity"].(float64); !ok || validity <= 0 || validity != float64(int64(validity)) {
+		c.error("$.presentation_max_validity", "must be a positive number of seconds")
+	}
+	definition, ok := document["presentation_definition"].(map[string]interface{})
+	if !ok {
+		c.error("$.presentation_definition", "must be an object")
+		return nil
+	}
+	c.presentationDefinition("$.presentation_definition", definition)
+	if definitionID, _ := definition["id"].(string); id != "" && definitionID != "" && definitionID != id {
+		c.warning("$.presentation_definition.id", "differs from the discovery service ID %q", id)
+	}
+	return map[string]map[string]interface{}{"$.presentation_definition": definition}
+}
+
+// policy checks a policy file, which maps scopes to a presentation definition per wallet owner type,
+// and returns its presentation definitions by path.
+func (c *checker) policy(document map[string]interface{}) map[string]map[string]interface{} {
+	result := map[string]map[string]interface{}{}
+	for _, scope := range sortedKeys(document) {
+		scopePath := memberPath("$", scope)
+		mapping, ok := document[scope].(map[string]interface{})
+		if !ok || len(mapping) == 0 {
+			c.error(scopePath, "scope must map wallet owner types to presentation definitions")
+			continue
+		}
+		for _, ownerType := range sortedKeys(mapping) {
+			path := memberPath(scopePath, ownerType)
+			if ownerType != "organization" && ownerType != "user" {
+				c.error(path, "unknown wallet owner type %q, expected organization or user", ownerType)
+				continue
+			}
+			definition, ok := mapping[ownerType].(map[string]interface{})
+			if !ok {
+				c.error(path, "must be an object")
+				continue
+			}
+			c.presentationDefinition(path, definition)
+			result[path] = definition
+		}
+	}
+	return result
+}
+
+func (c *checker) requiredString(path string, object map[string]interface{}, property string) string {
+	value, ok := object[property].(string)
+	if !ok || value == "" {
+		c.error(memberPath(path, property), "required, must be a non-empty string")
+	}
+	return value
+}
+
+func (c *checker) optionalString(path string, object map[string]interface{}, property string) {
+	if value, exists := object[property]; exists {
+		if _, ok := value.(string); !ok {
+			c.error(memberPath(path, property), "must be a string")
+		}
+	}
+}
+
+func (c *checker) optionalBool(path string, object map[string]interface{}, property string) {
+	if value, exists := object[property]; exists {
+		if _, ok := value.(bool); !ok {
+			c.error(memberPath(path, property), "must be a boolean")
+		}
+	}
+}
+
+func (c *checker) optionalEnum(path string, object map[string]interface{}, property string, options ...string) {
+	if value, exists := object[property]; exists {
+		if str, ok := value.(string); !ok || !slices.Contains(options, str) {
+			c.error(memberPath(path, property), "must be one of %s", strings.Join(options, ", "))
+		}
+	}
+}
+
+func (c *checker) unknownProperties(path string, object map[string]interface{}, known ...string) {
+	for _, property := range sortedKeys(object) {
+		if !slices.Contains(known, property) {
+			c.error(memberPath(path, property), "unknown property")
+		}
+	}
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	result := make([]string, 0, len(object))
+	for key := range object {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// memberPath returns the JSONPath of a member of the object at the given path.
+func memberPath(path string, name string) string {
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return fmt.Sprintf("%s['%s']", path, name)
+		}
+	}
+	return path + "." + name
+}
+
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
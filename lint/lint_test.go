@@ -0,0 +1,94 @@
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Run("deployed files", func(t *testing.T) {
+		files, err := ReadFiles([]string{"../deploy/discovery", "../deploy/policy"})
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+
+		report := Lint(files)
+
+		assert.True(t, report.Valid, "%v", report)
+		assert.Equal(t, KindDiscovery, report.Files[0].Kind)
+		assert.Equal(t, KindPolicy, report.Files[2].Kind)
+	})
+	t.Run("invalid JSON", func(t *testing.T) {
+		report := Lint([]File{{Name: "test.json", Content: json.RawMessage(`[]`)}})
+
+		assert.False(t, report.Valid)
+		assert.Equal(t, KindUnknown, report.Files[0].Kind)
+	})
+	t.Run("invalid discovery definition", func(t *testing.T) {
+		report := Lint([]File{{Name: "test.json", Content: json.RawMessage(`{
+  "id": "test",
+  "endpoint": "example.com",
+  "presentation_definition": {
+    "id": "other",
+    "format": {"jwt_vp": {"alg": ["ES255"]}, "mso_mdoc": {}},
+    "input_descriptors": [
+      {"id": "1", "constraints": {"fields": [{"path": ["$..type"], "filter": {"type": "string", "const": 1}}]}},
+      {"id": "1", "constraints": {"fields": [{"path": [], "filter": {"pattern": "("}, "predicate": "always"}, {"path": ["$.type", "$x"], "filter": {"items": {"oneOf": []}}}]}}
+    ]
+  }
+}`)}})
+
+		assert.False(t, report.Valid)
+		assert.Equal(t, []string{
+			"error: $.endpoint: must be an HTTP(S) URL",
+			"error: $.presentation_max_validity: must be a positive number of seconds",
+			"error: $.presentation_definition.format.jwt_vp.alg[0]: unknown alg \"ES255\"",
+			"error: $.presentation_definition.format.mso_mdoc: unknown format",
+			"warning: $.presentation_definition.input_descriptors[0].constraints.fields[0].path[0]: JSONPath is not supported by nuts-admin: \"$..type\" uses recursive descent (..)",
+			"error: $.presentation_definition.input_descriptors[0].constraints.fields[0].filter.const: doesn't match type string, so nothing matches the schema",
+			"error: $.presentation_definition.input_descriptors[1].constraints.fields[0].predicate: must be one of required, preferred",
+			"error: $.presentation_definition.input_descriptors[1].constraints.fields[0].path: must be a non-empty array of strings",
+			"error: $.presentation_definition.input_descriptors[1].constraints.fields[0].filter.pattern: error parsing regexp: missing closing ): `(`",
+			"error: $.presentation_definition.input_descriptors[1].constraints.fields[1].path[1]: invalid JSONPath \"$x\": unexpected 'x'",
+			"warning: $.presentation_definition.input_descriptors[1].constraints.fields[1].filter.items.oneOf: keyword is not supported by nuts-admin, so it can't check credentials against the filter",
+			"error: $.presentation_definition.input_descriptors[1].id: duplicate input descriptor ID \"1\"",
+			"warning: $.presentation_definition.id: differs from the discovery service ID \"test\"",
+		}, issueStrings(report.Files[0]))
+	})
+	t.Run("invalid policy", func(t *testing.T) {
+		report := Lint([]File{{Name: "test.json", Content: json.RawMessage(`{
+  "scope-1": {"admin": {}},
+  "scope-2": "pd",
+  "scope-3": {"organization": {"id": "pd", "input_descriptors": [{"id": "1"}], "submission_requirements": [{"rule": "pick", "from": "A"}]}}
+}`)}})
+
+		assert.Equal(t, []string{
+			"error: $['scope-1'].admin: unknown wallet owner type \"admin\", expected organization or user",
+			"error: $['scope-2']: scope must map wallet owner types to presentation definitions",
+			"error: $['scope-3'].organization.input_descriptors[0].constraints: required, must be an object",
+			"error: $['scope-3'].organization.submission_requirements[0].from: no input descriptor is in group \"A\"",
+		}, issueStrings(report.Files[0]))
+	})
+	t.Run("definition ID used by different definitions", func(t *testing.T) {
+		definition := func(name string) json.RawMessage {
+			return json.RawMessage(`{"scope": {"organization": {"id": "pd", "name": "` + name + `", "input_descriptors": [{"id": "1", "constraints": {}}]}}}`)
+		}
+
+		report := Lint([]File{{Name: "a.json", Content: definition("a")}, {Name: "b.json", Content: definition("a")}, {Name: "c.json", Content: definition("c")}})
+
+		assert.False(t, report.Valid)
+		assert.Empty(t, report.Files[0].Issues)
+		assert.Empty(t, report.Files[1].Issues)
+		assert.Equal(t, []string{"error: $.scope.organization: presentation definition ID \"pd\" is also used by a different presentation definition (a.json: $.scope.organization)"}, issueStrings(report.Files[2]))
+	})
+}
+
+func issueStrings(report FileReport) []string {
+	var result []string
+	for _, issue := range report.Issues {
+		result = append(result, issue.String())
+	}
+	return result
+}
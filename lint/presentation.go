@@ -0,0 +1,259 @@
+package lint
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/nuts-foundation/nuts-admin/jsonschema"
+	"github.com/nuts-foundation/nuts-admin/pe"
+)
+
+// knownFormats maps the formats of the Presentation Exchange claim format registry to the property that lists their algorithms.
+var knownFormats = map[string]string{
+	"jwt":    "alg",
+	"jwt_vc": "alg",
+	"jwt_vp": "alg",
+	"ldp":    "proof_type",
+	"ldp_vc": "proof_type",
+	"ldp_vp": "proof_type",
+}
+
+// supportedFormats are the formats the Nuts node supports.
+var supportedFormats = []string{"jwt_vc", "jwt_vp", "ldp_vc", "ldp_vp"}
+
+// knownAlgorithms are the JWS algorithms that can be used for JWT credentials and presentations.
+var knownAlgorithms = []string{"ES256", "ES384", "ES512", "ES256K", "PS256", "PS384", "PS512", "RS256", "RS384", "RS512", "EdDSA"}
+
+// knownProofTypes are the linked data proof types that can be used for JSON-LD credentials and presentations.
+var knownProofTypes = []string{"JsonWebSignature2020", "Ed25519Signature2018", "Ed25519Signature2020", "EcdsaSecp256k1Signature2019", "DataIntegrityProof"}
+
+// presentationDefinition checks a presentation definition against the Presentation Exchange v2 schema.
+func (c *checker) presentationDefinition(path string, definition map[string]interface{}) {
+	c.unknownProperties(path, definition, "id", "name", "purpose", "format", "frame", "submission_requirements", "input_descriptors")
+	c.requiredString(path, definition, "id")
+	c.optionalString(path, definition, "name")
+	c.optionalString(path, definition, "purpose")
+	if format, exists := definition["format"]; exists {
+		c.format(memberPath(path, "format"), format)
+	}
+	descriptorsPath := memberPath(path, "input_descriptors")
+	descriptors, ok := definition["input_descriptors"].([]interface{})
+	if !ok || len(descriptors) == 0 {
+		c.error(descriptorsPath, "required, must be a non-empty array")
+	}
+	var ids []string
+	var groups []string
+	for i, value := range descriptors {
+		descriptorPath := indexPath(descriptorsPath, i)
+		descriptor, ok := value.(map[string]interface{})
+		if !ok {
+			c.error(descriptorPath, "must be an object")
+			continue
+		}
+		id := c.inputDescriptor(descriptorPath, descriptor)
+		if id != "" && slices.Contains(ids, id) {
+			c.error(memberPath(descriptorPath, "id"), "duplicate input descriptor ID %q", id)
+		}
+		ids = append(ids, id)
+		groups = append(groups, c.stringArray(memberPath(descriptorPath, "group"), descriptor["group"], false)...)
+	}
+	if requirements, exists := definition["submission_requirements"]; exists {
+		c.submissionRequirements(memberPath(path, "submission_requirements"), requirements, groups)
+	}
+}
+
+func (c *checker) inputDescriptor(path string, descriptor map[string]interface{}) string {
+	c.unknownProperties(path, descriptor, "id", "name", "purpose", "format", "group", "constraints")
+	id := c.requiredString(path, descriptor, "id")
+	c.optionalString(path, descriptor, "name")
+	c.optionalString(path, descriptor, "purpose")
+	if format, exists := descriptor["format"]; exists {
+		c.format(memberPath(path, "format"), format)
+	}
+	constraintsPath := memberPath(path, "constraints")
+	constraints, ok := descriptor["constraints"].(map[string]interface{})
+	if !ok {
+		c.error(constraintsPath, "required, must be an object")
+		return id
+	}
+	c.unknownProperties(constraintsPath, constraints, "limit_disclosure", "statuses", "fields", "subject_is_issuer", "is_holder", "same_subject")
+	c.optionalEnum(constraintsPath, constraints, "limit_disclosure", "required", "preferred")
+	c.optionalEnum(constraintsPath, constraints, "subject_is_issuer", "required", "preferred")
+	fieldsPath := memberPath(constraintsPath, "fields")
+	fields, exists := constraints["fields"]
+	if !exists {
+		return id
+	}
+	items, ok := fields.([]interface{})
+	if !ok {
+		c.error(fieldsPath, "must be an array")
+		return id
+	}
+	for i, item := range items {
+		fieldPath := indexPath(fieldsPath, i)
+		field, ok := item.(map[string]interface{})
+		if !ok {
+			c.error(fieldPath, "must be an object")
+			continue
+		}
+		c.field(fieldPath, field)
+	}
+	return id
+}
+
+func (c *checker) field(path string, field map[string]interface{}) {
+	c.unknownProperties(path, field, "id", "optional", "path", "purpose", "name", "intent_to_retain", "filter", "predicate")
+	c.optionalString(path, field, "id")
+	c.optionalString(path, field, "purpose")
+	c.optionalString(path, field, "name")
+	c.optionalBool(path, field, "optional")
+	c.optionalBool(path, field, "intent_to_retain")
+	c.optionalEnum(path, field, "predicate", "required", "preferred")
+	pathsPath := memberPath(path, "path")
+	paths := c.stringArray(pathsPath, field["path"], true)
+	for i, expression := range paths {
+		err := pe.ValidatePath(expression)
+		switch {
+		case errors.Is(err, pe.ErrUnsupportedPath):
+			// The Nuts node may support it, but nuts-admin can't check credentials against it
+			c.warning(indexPath(pathsPath, i), "%s", err)
+		case err != nil:
+			c.error(indexPath(pathsPath, i), "%s", err)
+		}
+	}
+	filter, exists := field["filter"]
+	if !exists {
+		if _, hasPredicate := field["predicate"]; hasPredicate {
+			c.error(path, "predicate requires a filter")
+		}
+		return
+	}
+	c.filter(memberPath(path, "filter"), filter)
+}
+
+// filter checks a filter by compiling it the way nuts-admin evaluates filters (see pe.Filter).
+// Keywords nuts-admin doesn't support are reported as warning: the Nuts node may support them, but nuts-admin can't match credentials against the filter.
+func (c *checker) filter(path string, value interface{}) {
+	filter, ok := value.(map[string]interface{})
+	if !ok {
+		c.error(path, "must be an object")
+		return
+	}
+	_, err := jsonschema.Compile(filter)
+	var schemaErr *jsonschema.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return
+	}
+	keywordPath := path + strings.TrimPrefix(schemaErr.Path, "$")
+	if errors.Is(err, jsonschema.ErrUnsupportedKeyword) {
+		c.warning(keywordPath, "keyword is not supported by nuts-admin, so it can't check credentials against the filter")
+		return
+	}
+	c.error(keywordPath, "%s", schemaErr.Err)
+}
+
+// format checks a claim format designation, e.g. {"jwt_vp": {"alg": ["ES256"]}}.
+func (c *checker) format(path string, value interface{}) {
+	formats, ok := value.(map[string]interface{})
+	if !ok || len(formats) == 0 {
+		c.error(path, "must be a non-empty object")
+		return
+	}
+	for _, name := range sortedKeys(formats) {
+		formatPath := memberPath(path, name)
+		property, known := knownFormats[name]
+		if !known {
+			c.error(formatPath, "unknown format")
+			continue
+		}
+		if !slices.Contains(supportedFormats, name) {
+			c.warning(formatPath, "format is not supported by the Nuts node")
+		}
+		designation, ok := formats[name].(map[string]interface{})
+		if !ok {
+			c.error(formatPath, "must be an object")
+			continue
+		}
+		c.unknownProperties(formatPath, designation, property)
+		allowed := knownAlgorithms
+		if property == "proof_type" {
+			allowed = knownProofTypes
+		}
+		algorithmsPath := memberPath(formatPath, property)
+		for i, algorithm := range c.stringArray(algorithmsPath, designation[property], true) {
+			if !slices.Contains(allowed, algorithm) {
+				c.error(indexPath(algorithmsPath, i), "unknown %s %q", strings.ReplaceAll(property, "_", " "), algorithm)
+			}
+		}
+	}
+}
+
+func (c *checker) submissionRequirements(path string, value interface{}, groups []string) {
+	requirements, ok := value.([]interface{})
+	if !ok {
+		c.error(path, "must be an array")
+		return
+	}
+	for i, item := range requirements {
+		requirementPath := indexPath(path, i)
+		requirement, ok := item.(map[string]interface{})
+		if !ok {
+			c.error(requirementPath, "must be an object")
+			continue
+		}
+		c.unknownProperties(requirementPath, requirement, "name", "purpose", "rule", "count", "min", "max", "from", "from_nested")
+		c.optionalString(requirementPath, requirement, "name")
+		c.optionalString(requirementPath, requirement, "purpose")
+		if rule, _ := requirement["rule"].(string); rule != "all" && rule != "pick" {
+			c.error(memberPath(requirementPath, "rule"), "required, must be one of all, pick")
+		}
+		for _, property := range []string{"count", "min", "max"} {
+			if number, exists := requirement[property]; exists {
+				if n, ok := number.(float64); !ok || n < 0 || n != float64(int64(n)) {
+					c.error(memberPath(requirementPath, property), "must be a non-negative integer")
+				}
+			}
+		}
+		from, hasFrom := requirement["from"]
+		nested, hasNested := requirement["from_nested"]
+		switch {
+		case hasFrom == hasNested:
+			c.error(requirementPath, "requires either from or from_nested")
+		case hasFrom:
+			group, ok := from.(string)
+			if !ok {
+				c.error(memberPath(requirementPath, "from"), "must be a string")
+			} else if !slices.Contains(groups, group) {
+				c.error(memberPath(requirementPath, "from"), "no input descriptor is in group %q", group)
+			}
+		default:
+			c.submissionRequirements(memberPath(requirementPath, "from_nested"), nested, groups)
+		}
+	}
+}
+
+// stringArray returns the strings in the array, reporting an error if the value isn't an array of strings.
+func (c *checker) stringArray(path string, value interface{}, required bool) []string {
+	if value == nil {
+		if required {
+			c.error(path, "required, must be a non-empty array of strings")
+		}
+		return nil
+	}
+	items, ok := value.([]interface{})
+	if !ok || (required && len(items) == 0) {
+		c.error(path, "must be a non-empty array of strings")
+		return nil
+	}
+	var result []string
+	for i, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			c.error(indexPath(path, i), "must be a string")
+			continue
+		}
+		result = append(result, str)
+	}
+	return result
+}
@@ -1,18 +1,22 @@
 package main
 
 import (
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/rsa"
 	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,13 +24,27 @@ import (
 	"github.com/lestrrat-go/jwx/jwk"
 	"github.com/lestrrat-go/jwx/jwt"
 	libDiscovery "github.com/nuts-foundation/go-nuts-client/nuts/discovery"
+	"github.com/nuts-foundation/go-nuts-client/nuts/iam"
 	"github.com/nuts-foundation/go-nuts-client/nuts/vcr"
 	"github.com/nuts-foundation/go-nuts-client/nuts/vdr"
+	"github.com/nuts-foundation/nuts-admin/capture"
+	"github.com/nuts-foundation/nuts-admin/credentialprofile"
 	"github.com/nuts-foundation/nuts-admin/discovery"
+	"github.com/nuts-foundation/nuts-admin/expiry"
 	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/inspector"
 	"github.com/nuts-foundation/nuts-admin/issuer"
+	"github.com/nuts-foundation/nuts-admin/job"
+	"github.com/nuts-foundation/nuts-admin/model"
 	"github.com/nuts-foundation/nuts-admin/oidc"
+	"github.com/nuts-foundation/nuts-admin/openid4vci"
+	"github.com/nuts-foundation/nuts-admin/policy"
+	"github.com/nuts-foundation/nuts-admin/provisioning"
+	"github.com/nuts-foundation/nuts-admin/registration"
+	"github.com/nuts-foundation/nuts-admin/reload"
+	"github.com/nuts-foundation/nuts-admin/store"
 	"github.com/rs/zerolog"
+	"github.com/spf13/pflag"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lestrrat-go/jwx/jwa"
@@ -66,28 +84,83 @@ func redirectSkipper(c echo.Context) bool {
 
 func main() {
 	logger = zerolog.New(log.Writer()).With().Timestamp().Logger()
-	config := loadConfig()
+	os.Exit(execute(os.Stdout, rootCommand(), os.Args[1:]))
+}
+
+func serveCommand() *command {
+	return &command{
+		name:  "serve",
+		short: "Start the server (default)",
+		flags: func(flags *pflag.FlagSet) {
+			flags.Bool("live", false, "Serve the web assets from the file system instead of the embedded files, for front-end development")
+		},
+		run: func(_ io.Writer, flags *pflag.FlagSet, _ []string) int {
+			live, _ := flags.GetBool("live")
+			serve(resolveConfigFile(flags), live)
+			return 0
+		},
+	}
+}
+
+// nodeServices are the services that call the Nuts node, shared by the server and the commands.
+type nodeServices struct {
+	identity  identity.Service
+	discovery discovery.Service
+	issuer    issuer.Service
+}
+
+// newNodeServices creates the services, which use the given HTTP client to call the Nuts node.
+func newNodeServices(config Config, httpClient api.HTTPRequestDoer) nodeServices {
+	vdrClient, _ := vdr.NewClient(config.Node.Address, vdr.WithHTTPClient(httpClient))
+	vcrClient, _ := vcr.NewClient(config.Node.Address, vcr.WithHTTPClient(httpClient))
+	iamClient, _ := iam.NewClient(config.Node.Address, iam.WithHTTPClient(httpClient))
+	discoveryClient, _ := libDiscovery.NewClient(config.Node.Address, libDiscovery.WithHTTPClient(httpClient))
+	discoveryService := discovery.Service{
+		Client: discoveryClient,
+	}
+	identityService := identity.Service{
+		VDRClient:        vdrClient,
+		VCRClient:        vcrClient,
+		IAMClient:        iamClient,
+		DiscoveryService: discoveryService,
+		ServiceProfiles:  config.ServiceProfiles,
+	}
+	return nodeServices{
+		identity:  identityService,
+		discovery: discoveryService,
+		issuer: issuer.Service{
+			IdentityService: identityService,
+			VCRClient:       vcrClient,
+		},
+	}
+}
+
+// serve starts the server. It exits the process if the server can't be started or stops.
+func serve(configFilePath string, useFS bool) {
+	config := loadConfig(configFilePath)
 	config.Print()
 	if err := config.Validate(); err != nil {
 		logger.Fatal().Err(err).Msg("invalid config")
 		os.Exit(1)
 	}
+	logLevel, _ := zerolog.ParseLevel(config.LogLevel)
+	zerolog.SetGlobalLevel(logLevel)
 
 	e := echo.New()
 	e.HideBanner = true
 	e.HTTPErrorHandler = httpErrorHandler
 	e.HideBanner = true
 	e.HidePort = true
-	if config.AccessLogs {
-		e.Use(accessLoggerMiddleware(func(c echo.Context) bool {
-			return c.Request().URL.Path == "/status"
-		}, logger))
-	}
+	e.IPExtractor = config.ipExtractor()
+	// Access logs can be switched on and off by reloading the config
+	accessLogs := &atomic.Bool{}
+	accessLogs.Store(config.AccessLogs)
+	e.Use(accessLoggerMiddleware(func(c echo.Context) bool {
+		return !accessLogs.Load() || c.Request().URL.Path == "/status"
+	}, logger))
 
-	nodeAddress, err := url.Parse(config.Node.Address)
-	if err != nil {
-		log.Fatalf("unable to parse node address: %s", err)
-	}
+	// The node address is checked by Config.Validate
+	nodeAddress, _ := url.Parse(config.Node.Address)
 
 	if config.OIDC.Enabled {
 		err := oidc.Setup(config.OIDC, config.BaseURL, e, oidc.AuthConfig{
@@ -108,35 +181,117 @@ func main() {
 	//	tokenGenerator = createTokenGenerator(config)
 	//}
 
-	vdrClient, _ := vdr.NewClient(config.Node.Address)
-	vcrClient, _ := vcr.NewClient(config.Node.Address)
-	discoveryClient, _ := libDiscovery.NewClient(config.Node.Address)
+	// The routes of the proxy also apply to the calls the typed endpoints make to the Nuts node, even if the proxy is disabled
+	proxy, err := api.NewProxy(config.Proxy)
+	if err != nil {
+		log.Fatalf("unable to configure proxy: %s", err)
+	}
+	e.Use(api.RecordCaller)
 
 	// Initialize wrapper
-	discoveryService := discovery.Service{
-		Client: discoveryClient,
+	services := newNodeServices(config, proxy.NodeClient(nodeAddress, http.DefaultClient))
+	discoveryService := services.discovery
+	identityService := services.identity
+	vcrClient := services.issuer.VCRClient
+	jobRunner, err := job.NewRunner(filepath.Join(config.DataDir, "jobs"))
+	if err != nil {
+		log.Fatalf("unable to initialize job runner: %s", err)
 	}
-	identityService := identity.Service{
-		VDRClient:        vdrClient,
+	revocationRecords, err := store.Open[issuer.RevocationRecord](filepath.Join(config.DataDir, "revocations.json"))
+	if err != nil {
+		log.Fatalf("unable to load revocation records: %s", err)
+	}
+	issuerService := services.issuer
+	issuerService.Jobs = jobRunner
+	issuerService.RevocationRecords = revocationRecords
+	issuerService.RegisterJobHandlers()
+	provisioningService := provisioning.Service{
+		IdentityService:  identityService,
+		IssuerService:    issuerService,
+		DiscoveryService: discoveryService,
+		Jobs:             jobRunner,
+	}
+	provisioningService.RegisterJobHandlers()
+	expiryMonitor := expiry.NewMonitor(config.Expiry, identityService, issuerService, logger)
+	expiryMonitor.Start(context.Background())
+	registrationHistory, err := store.Open[registration.Snapshot](filepath.Join(config.DataDir, "registration-history.json"))
+	if err != nil {
+		log.Fatalf("unable to load discovery registration history: %s", err)
+	}
+	registrationMonitor := registration.NewMonitor(config.Registration, identityService, discoveryService, registrationHistory, logger)
+	registrationMonitor.Start(context.Background())
+	policyService := policy.Service{
+		Config:          config.Policy,
+		IdentityService: identityService,
+	}
+	inspectorService := inspector.Service{
 		VCRClient:        vcrClient,
+		IdentityService:  identityService,
 		DiscoveryService: discoveryService,
 	}
+	var proxyCaptures *capture.Recorder
+	if config.Proxy.Enabled && config.Proxy.Capture.Enabled {
+		proxyCaptures = capture.NewRecorder(config.Proxy.Capture)
+	}
+	credentialProfiles := &atomic.Pointer[[]model.CredentialProfile]{}
+	credentialProfiles.Store(&config.CredentialProfiles)
+	managedCredentialProfiles, err := store.Open[model.CredentialProfile](filepath.Join(config.DataDir, "credential-profiles.json"))
+	if err != nil {
+		log.Fatalf("unable to load credential profiles: %s", err)
+	}
+	credentialRequests, err := store.Open[openid4vci.Request](filepath.Join(config.DataDir, "credential-requests.json"))
+	if err != nil {
+		log.Fatalf("unable to load credential requests: %s", err)
+	}
+	reloader := &configReloader{
+		current:            config,
+		accessLogs:         accessLogs,
+		credentialProfiles: credentialProfiles,
+		proxy:              proxy,
+		expiry:             expiryMonitor,
+	}
+	// The config file is watched by its absolute path, since the directory it's in is watched
+	if reloader.path, err = filepath.Abs(configFilePath); err != nil {
+		log.Fatalf("unable to resolve config file path: %s", err)
+	}
+	configWatcher := reload.NewWatcher(reloader.path, reloader.reload, logger)
 	apiWrapper := api.Wrapper{
-		Identity:  identityService,
-		Discovery: discoveryService,
-		IssuerService: issuer.Service{
-			IdentityService: identityService,
-			VCRClient:       vcrClient,
+		Identity:      identityService,
+		Discovery:     discoveryService,
+		IssuerService: issuerService,
+		Provisioning:  provisioningService,
+		Jobs:          jobRunner,
+		Expiry:        expiryMonitor,
+		Registrations: registrationMonitor,
+		Inspector:     inspectorService,
+		Policy:        policyService,
+		ProxyCaptures: proxyCaptures,
+		CredentialProfiles: credentialprofile.Service{
+			Configured: credentialProfiles,
+			Managed:    managedCredentialProfiles,
+		},
+		CredentialRequests: openid4vci.Tracker{
+			Identity: identityService,
+			Requests: credentialRequests,
+			BaseURL:  config.BaseURL,
 		},
-		CredentialProfiles: config.CredentialProfiles,
+		ConfigReload: configWatcher,
 	}
 
 	api.RegisterHandlers(e, apiWrapper)
-	api.ConfigureProxy(logger, e, nodeAddress)
+	if config.BaseURL == "" {
+		logger.Warn().Msg("Credentials can't be requested, since url isn't set (it's needed for the redirect URI of the issuer)")
+	}
+	if config.Proxy.Enabled {
+		logger.Warn().Msg("The proxy to the Nuts node's internal API is deprecated, set proxy.enabled to false to disable it")
+		api.ConfigureProxy(logger, e, nodeAddress, proxy, proxyCaptures)
+	}
+	if err := configWatcher.Start(); err != nil {
+		logger.Warn().Err(err).Msgf("Changes to %s won't be applied while running", configFilePath)
+	}
 
 	// Setup asset serving:
 	// Check if we use live mode from the file system or using embedded files
-	useFS := len(os.Args) > 1 && os.Args[1] == "live"
 	assetHandler := http.FileServer(getFileSystem(useFS))
 	e.GET("/status", func(context echo.Context) error {
 		return context.String(http.StatusOK, "OK")
@@ -156,7 +311,9 @@ func httpErrorHandler(err error, c echo.Context) {
 	)
 	type Map map[string]interface{}
 
-	if he, ok := err.(*echo.HTTPError); ok {
+	// Services wrap the errors of the Nuts node client, e.g. when a call to the Nuts node isn't allowed (see api.Proxy.NodeClient)
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
 		code = he.Code
 		msg = he.Message
 		if he.Internal != nil {
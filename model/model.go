@@ -9,9 +9,37 @@ import (
 
 type VerifiableCredential vc.VerifiableCredential
 
+// CredentialProfile describes a credential that subjects can request from an issuer using OpenID4VCI.
 type CredentialProfile struct {
-	Type   string `json:"type" koanf:"type"`
+	// ID identifies the profile. It defaults to the type for profiles in the config file.
+	ID   string `json:"id" koanf:"id"`
+	Type string `json:"type" koanf:"type"`
+	// Issuer is the DID or URL of the credential issuer.
 	Issuer string `json:"issuer" koanf:"issuer"`
+	// Name is the name that is displayed for the profile. It defaults to the type.
+	Name        string `json:"name,omitempty" koanf:"name"`
+	Description string `json:"description,omitempty" koanf:"description"`
+	// CredentialConfigurationID is the ID of the credential in the issuer's metadata. It defaults to the type.
+	CredentialConfigurationID string `json:"credential_configuration_id,omitempty" koanf:"credentialconfigurationid"`
+	// AuthorizationServer overrides the authorization server from the issuer's metadata.
+	AuthorizationServer string `json:"authorization_server,omitempty" koanf:"authorizationserver"`
+	// DIDMethod is the DID method the wallet DID must have, e.g. web. If empty, any DID of the subject can be used.
+	DIDMethod string `json:"did_method,omitempty" koanf:"didmethod"`
+	// AllowedSubjects contains the subjects that may request the credential. If empty, all subjects may request it.
+	AllowedSubjects []string `json:"allowed_subjects,omitempty" koanf:"allowedsubjects"`
+	// Managed indicates the profile is managed through the API, instead of the config file.
+	Managed bool `json:"managed" koanf:"-"`
+}
+
+// ServiceProfile describes the expected shape of DID services of a specific type.
+type ServiceProfile struct {
+	Type string `json:"type" koanf:"type"`
+	// Compound indicates the service endpoint is an object with named URLs, instead of a single URL.
+	Compound bool `json:"compound" koanf:"compound"`
+	// RequiredKeys contains the keys a compound service endpoint must contain.
+	RequiredKeys []string `json:"required_keys,omitempty" koanf:"requiredkeys"`
+	// AllowHTTP allows plain HTTP endpoint URLs. By default, only HTTPS URLs are accepted.
+	AllowHTTP bool `json:"allow_http" koanf:"allowhttp"`
 }
 
 type CredentialWithStatus struct {
@@ -2,6 +2,8 @@ package oidc
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -10,11 +12,13 @@ type Config struct {
 	MetadataURL string       `koanf:"metadata"`
 	Client      ClientConfig `koanf:"client"`
 	Scope       []string     `koanf:"scope"`
+	// RolesClaim is the ID token claim that contains the roles of the user, used to authorize proxy routes.
+	RolesClaim string `koanf:"rolesclaim"`
 }
 
 type ClientConfig struct {
 	ID     string `koanf:"id"`
-	Secret string `koanf:"secret"`
+	Secret string `koanf:"secret" secret:"true"`
 }
 
 func DefaultConfig() Config {
@@ -25,36 +29,44 @@ func DefaultConfig() Config {
 			"profile",
 			"email",
 		},
+		RolesClaim: "roles",
 	}
 }
 
+// Validate checks the config if OIDC is enabled, reporting all problems at once.
 func (c Config) Validate() error {
 	if !c.Enabled {
 		return nil
 	}
+	var errs []error
 
 	if c.MetadataURL == "" {
-		return errors.New("metadata_url is required")
+		errs = append(errs, errors.New("metadata is required"))
+	} else if metadataURL, err := url.Parse(c.MetadataURL); err != nil || !metadataURL.IsAbs() {
+		errs = append(errs, fmt.Errorf("metadata must be an absolute URL: %s", c.MetadataURL))
 	}
 
 	if c.Client.ID == "" {
-		return errors.New("client_id is required")
+		errs = append(errs, errors.New("client.id is required"))
 	}
 
 	if c.Client.Secret == "" {
-		return errors.New("client_secret is required")
+		errs = append(errs, errors.New("client.secret is required"))
 	}
 
-	scopeCount := 0
 	for _, scope := range c.Scope {
 		if strings.TrimSpace(scope) == "" {
-			return errors.New("scope cannot be an empty string")
+			errs = append(errs, errors.New("scope cannot be an empty string"))
+			break
 		}
-		scopeCount++
 	}
-	if scopeCount == 0 {
-		return errors.New("at lease once scope is required")
+	if len(c.Scope) == 0 {
+		errs = append(errs, errors.New("at least one scope is required"))
 	}
 
-	return nil
+	if c.RolesClaim == "" {
+		errs = append(errs, errors.New("rolesclaim is required"))
+	}
+
+	return errors.Join(errs...)
 }
@@ -17,17 +17,22 @@ import (
 	"github.com/quasoft/memstore"
 )
 
+// rolesContextKey is the key of the authenticated user's roles in the echo context.
+const rolesContextKey = "oidc.roles"
+
 type OIDC struct {
 	baseURL     string
 	signInUrl   string
 	callbackURL string
+	rolesClaim  string
 }
 
 func Setup(config Config, baseURL string, e *echo.Echo, authConfig AuthConfig) error {
 	const name = "openid-connect"
 
 	o := &OIDC{
-		baseURL: baseURL,
+		baseURL:    baseURL,
+		rolesClaim: config.RolesClaim,
 	}
 
 	normalizedBaseUrl := strings.TrimRight(baseURL, "/")
@@ -98,6 +103,10 @@ func (o *OIDC) RegisterHandlers(e *echo.Echo) {
 		if err != nil {
 			return c.String(http.StatusBadRequest, err.Error())
 		}
+		err = gothic.StoreInSession("Roles", strings.Join(claimValues(user.RawData[o.rolesClaim]), " "), req, res)
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
 
 		return c.Redirect(http.StatusTemporaryRedirect, o.baseURL)
 	})
@@ -155,7 +164,32 @@ func AuthWithConfig(config AuthConfig) echo.MiddlewareFunc {
 			}
 
 			// Authorized, continue
+			roles, _ := gothic.GetFromSession("Roles", req)
+			c.Set(rolesContextKey, strings.Fields(roles))
 			return next(c)
 		}
 	}
 }
+
+// Roles returns the roles of the authenticated user, or nil if the request isn't authenticated (e.g. because OIDC is disabled).
+func Roles(c echo.Context) []string {
+	roles, _ := c.Get(rolesContextKey).([]string)
+	return roles
+}
+
+// claimValues returns the values of a claim that is either a single string or an array of strings.
+func claimValues(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		var result []string
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
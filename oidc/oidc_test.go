@@ -121,3 +121,9 @@ func TestOIDC(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 	})
 }
+
+func Test_claimValues(t *testing.T) {
+	assert.Equal(t, []string{"admin", "issuer"}, claimValues([]interface{}{"admin", 1, "issuer"}))
+	assert.Equal(t, []string{"admin", "issuer"}, claimValues("admin issuer"))
+	assert.Empty(t, claimValues(nil))
+}
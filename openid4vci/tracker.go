@@ -0,0 +1,212 @@
+// Package openid4vci tracks the OpenID4VCI flows that are started through nuts-admin.
+// The issuer sends the user back to a redirect URI owned by nuts-admin, which confirms the credential landed in the wallet.
+package openid4vci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	ssi "github.com/nuts-foundation/go-did"
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/nuts-foundation/nuts-admin/store"
+)
+
+// CallbackPath is the path of the endpoint the issuer redirects the user to after the authorization.
+const CallbackPath = "/api/openid4vci/callback"
+
+// ErrNotFound is returned when the state of a callback doesn't refer to a credential request.
+var ErrNotFound = errors.New("credential request not found")
+
+// ErrNoBaseURL is returned when a credential request is started, but the external URL of nuts-admin isn't configured.
+var ErrNoBaseURL = errors.New("url must be configured to request credentials, it's needed for the redirect URI")
+
+// Status is the status of a credential request.
+type Status string
+
+const (
+	// StatusPending indicates the user was redirected to the issuer, and didn't return yet.
+	StatusPending Status = "pending"
+	// StatusSucceeded indicates the issued credential is in the wallet.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed indicates the request was refused, or the credential isn't in the wallet.
+	StatusFailed Status = "failed"
+)
+
+// Request is the record of a credential request of a subject.
+type Request struct {
+	// ID identifies the request. It's the state parameter of the redirect URI.
+	ID        string `json:"id"`
+	Subject   string `json:"subject"`
+	WalletDID string `json:"wallet_did"`
+	Issuer    string `json:"issuer"`
+	ProfileID string `json:"profile_id,omitempty"`
+	// CredentialType is the type the issued credential must have. If empty, any new credential in the wallet is accepted.
+	CredentialType string `json:"credential_type,omitempty"`
+	Status         Status `json:"status"`
+	// Error describes why the request failed.
+	Error string `json:"error,omitempty"`
+	// CredentialID is the ID of the issued credential in the wallet.
+	CredentialID string     `json:"credential_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	// ExistingCredentialIDs contains the IDs of the credentials that were in the wallet when the request was made,
+	// to tell the issued credential apart.
+	ExistingCredentialIDs []string `json:"existing_credential_ids,omitempty"`
+}
+
+// Tracker starts credential requests and completes them when the issuer redirects the user back.
+type Tracker struct {
+	Identity identity.Service
+	Requests *store.Collection[Request]
+	// BaseURL is the external URL of nuts-admin, which the issuer redirects the user back to. It's required to start requests.
+	BaseURL string
+}
+
+// Enabled returns whether credential requests can be started, which requires the base URL.
+func (t Tracker) Enabled() bool {
+	return t.BaseURL != ""
+}
+
+// Start records the credential request and starts the OpenID4VCI flow, with the redirect URI set to the callback of nuts-admin.
+// It returns the URL the user must be redirected to, to authorize the issuance.
+func (t Tracker) Start(ctx context.Context, subjectID string, request identity.CredentialRequest, credentialType string) (*identity.CredentialRequestResult, error) {
+	if t.BaseURL == "" {
+		return nil, ErrNoBaseURL
+	}
+	walletCredentials, err := t.Identity.WalletCredentials(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	record := Request{
+		ID:             uuid.NewString(),
+		Subject:        subjectID,
+		WalletDID:      request.WalletDID,
+		Issuer:         request.Issuer,
+		ProfileID:      request.ProfileID,
+		CredentialType: credentialType,
+		Status:         StatusPending,
+		CreatedAt:      time.Now(),
+	}
+	for _, credential := range walletCredentials {
+		if credential.ID != nil {
+			record.ExistingCredentialIDs = append(record.ExistingCredentialIDs, credential.ID.String())
+		}
+	}
+	request.RedirectURI = t.baseURL() + CallbackPath + "?" + url.Values{"state": {record.ID}}.Encode()
+	result, err := t.Identity.RequestCredential(ctx, subjectID, request)
+	if errors.Is(err, identity.ErrInvalidCredentialRequest) {
+		return nil, err
+	} else if err != nil {
+		record.fail(err.Error())
+	}
+	if err := t.Requests.Add(record); err != nil {
+		return nil, err
+	}
+	return result, err
+}
+
+// Complete handles the callback of the credential request with the given state. If the issuer reported an error,
+// the request fails. Otherwise, it succeeds if the wallet of the subject contains a new credential of the expected type.
+// Requests that are already completed are returned as they are, so the callback can be repeated.
+func (t Tracker) Complete(ctx context.Context, state string, errorCode string, errorDescription string) (*Request, error) {
+	record, err := t.get(state)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != StatusPending {
+		return record, nil
+	}
+	if errorCode != "" {
+		record.fail(strings.TrimSuffix(errorCode+": "+errorDescription, ": "))
+	} else if walletCredentials, err := t.Identity.WalletCredentials(ctx, record.Subject); err != nil {
+		record.fail(fmt.Sprintf("unable to list the credentials in the wallet: %s", err))
+	} else {
+		record.confirm(walletCredentials)
+	}
+	err = t.Requests.Update(func(requests []Request) ([]Request, error) {
+		index := slices.IndexFunc(requests, func(other Request) bool { return other.ID == record.ID })
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, state)
+		}
+		requests[index] = *record
+		return requests, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// List returns the credential requests of the subject, newest first.
+func (t Tracker) List(subjectID string) []Request {
+	result := make([]Request, 0)
+	for _, request := range t.Requests.List() {
+		if request.Subject == subjectID {
+			result = append(result, request)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b Request) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return result
+}
+
+// ResultURL returns the page of the web UI that shows the result of the credential request.
+// If no base URL is configured (anymore), the URL is relative to the callback.
+func (t Tracker) ResultURL(request Request) string {
+	return t.baseURL() + "/#/admin/id/" + url.PathEscape(request.Subject) + "?" + url.Values{"credential_request": {request.ID}}.Encode()
+}
+
+func (t Tracker) get(id string) (*Request, error) {
+	for _, request := range t.Requests.List() {
+		if request.ID == id {
+			return &request, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+func (t Tracker) baseURL() string {
+	return strings.TrimSuffix(t.BaseURL, "/")
+}
+
+// confirm completes the request using the credentials that are in the wallet now:
+// it succeeds if there's a credential of the expected type that wasn't in the wallet when the request was made.
+func (r *Request) confirm(walletCredentials []model.CredentialWithStatus) {
+	for _, credential := range walletCredentials {
+		credentialVC := vc.VerifiableCredential(credential.VerifiableCredential)
+		if credentialVC.ID == nil || slices.Contains(r.ExistingCredentialIDs, credentialVC.ID.String()) {
+			continue
+		}
+		if r.CredentialType != "" && !slices.ContainsFunc(credentialVC.Type, func(credentialType ssi.URI) bool {
+			return credentialType.String() == r.CredentialType
+		}) {
+			continue
+		}
+		now := time.Now()
+		r.Status = StatusSucceeded
+		r.CredentialID = credentialVC.ID.String()
+		r.CompletedAt = &now
+		return
+	}
+	if r.CredentialType != "" {
+		r.fail(fmt.Sprintf("no new %s credential in the wallet", r.CredentialType))
+	} else {
+		r.fail("no new credential in the wallet")
+	}
+}
+
+func (r *Request) fail(reason string) {
+	now := time.Now()
+	r.Status = StatusFailed
+	r.Error = reason
+	r.CompletedAt = &now
+}
@@ -0,0 +1,141 @@
+package openid4vci
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/nuts-foundation/nuts-admin/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_confirm(t *testing.T) {
+	credential := func(t *testing.T, id string, credentialType string) model.CredentialWithStatus {
+		result, err := vc.ParseVerifiableCredential(`{
+  "@context": ["https://www.w3.org/2018/credentials/v1"],
+  "id": "` + id + `",
+  "type": ["VerifiableCredential", "` + credentialType + `"],
+  "issuer": "did:web:issuer.example.com",
+  "issuanceDate": "2024-01-01T00:00:00Z",
+  "credentialSubject": {"id": "did:web:example.com:iam:hospital"}
+}`)
+		require.NoError(t, err)
+		return model.CredentialWithStatus{VerifiableCredential: model.VerifiableCredential(*result)}
+	}
+	pending := Request{
+		ID:                    "1",
+		Subject:               "hospital",
+		CredentialType:        "HealthcareProviderCredential",
+		Status:                StatusPending,
+		ExistingCredentialIDs: []string{"did:web:issuer.example.com#old"},
+	}
+
+	t.Run("new credential of the type", func(t *testing.T) {
+		request := pending
+
+		request.confirm([]model.CredentialWithStatus{
+			credential(t, "did:web:issuer.example.com#old", "HealthcareProviderCredential"),
+			credential(t, "did:web:issuer.example.com#new", "HealthcareProviderCredential"),
+		})
+
+		assert.Equal(t, StatusSucceeded, request.Status)
+		assert.Equal(t, "did:web:issuer.example.com#new", request.CredentialID)
+		assert.NotNil(t, request.CompletedAt)
+	})
+	t.Run("only existing credentials", func(t *testing.T) {
+		request := pending
+
+		request.confirm([]model.CredentialWithStatus{credential(t, "did:web:issuer.example.com#old", "HealthcareProviderCredential")})
+
+		assert.Equal(t, StatusFailed, request.Status)
+		assert.Equal(t, "no new HealthcareProviderCredential credential in the wallet", request.Error)
+	})
+	t.Run("new credential of another type", func(t *testing.T) {
+		request := pending
+
+		request.confirm([]model.CredentialWithStatus{credential(t, "did:web:issuer.example.com#new", "NutsOrganizationCredential")})
+
+		assert.Equal(t, StatusFailed, request.Status)
+	})
+	t.Run("any type", func(t *testing.T) {
+		request := pending
+		request.CredentialType = ""
+
+		request.confirm([]model.CredentialWithStatus{credential(t, "did:web:issuer.example.com#new", "NutsOrganizationCredential")})
+
+		assert.Equal(t, StatusSucceeded, request.Status)
+	})
+}
+
+func TestTracker(t *testing.T) {
+	setup := func(t *testing.T, requests ...Request) (Tracker, string) {
+		path := filepath.Join(t.TempDir(), "credential-requests.json")
+		collection, err := store.Open[Request](path)
+		require.NoError(t, err)
+		require.NoError(t, collection.Add(requests...))
+		return Tracker{Requests: collection}, path
+	}
+	now := time.Now()
+
+	t.Run("complete with an error of the issuer", func(t *testing.T) {
+		tracker, path := setup(t, Request{ID: "1", Subject: "hospital", Status: StatusPending, CreatedAt: now})
+
+		result, err := tracker.Complete(context.Background(), "1", "access_denied", "user cancelled")
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Equal(t, "access_denied: user cancelled", result.Error)
+		reopened, err := store.Open[Request](path)
+		require.NoError(t, err)
+		assert.Equal(t, StatusFailed, reopened.List()[0].Status)
+	})
+	t.Run("complete a completed request", func(t *testing.T) {
+		tracker, _ := setup(t, Request{ID: "1", Subject: "hospital", Status: StatusSucceeded, CredentialID: "did:web:issuer.example.com#1", CreatedAt: now})
+
+		result, err := tracker.Complete(context.Background(), "1", "", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, result.Status)
+	})
+	t.Run("complete an unknown request", func(t *testing.T) {
+		tracker, _ := setup(t)
+
+		_, err := tracker.Complete(context.Background(), "1", "", "")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("list, newest first", func(t *testing.T) {
+		tracker, _ := setup(t,
+			Request{ID: "1", Subject: "hospital", CreatedAt: now.Add(-time.Hour)},
+			Request{ID: "2", Subject: "pharmacy", CreatedAt: now},
+			Request{ID: "3", Subject: "hospital", CreatedAt: now},
+		)
+
+		result := tracker.List("hospital")
+
+		require.Len(t, result, 2)
+		assert.Equal(t, "3", result[0].ID)
+		assert.Equal(t, "1", result[1].ID)
+	})
+	t.Run("start without base URL", func(t *testing.T) {
+		tracker, _ := setup(t)
+
+		_, err := tracker.Start(context.Background(), "hospital", identity.CredentialRequest{}, "")
+
+		assert.ErrorIs(t, err, ErrNoBaseURL)
+		assert.Empty(t, tracker.Requests.List())
+	})
+	t.Run("result URL", func(t *testing.T) {
+		tracker, _ := setup(t)
+		request := Request{ID: "1", Subject: "hospital x"}
+
+		assert.Equal(t, "/#/admin/id/hospital%20x?credential_request=1", tracker.ResultURL(request))
+		tracker.BaseURL = "https://admin.example.com/"
+		assert.Equal(t, "https://admin.example.com/#/admin/id/hospital%20x?credential_request=1", tracker.ResultURL(request))
+	})
+}
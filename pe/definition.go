@@ -0,0 +1,308 @@
+// Package pe implements the parts of DIF Presentation Exchange that nuts-admin needs to check credentials
+// against the presentation definitions of discovery services and policies, without a Nuts node.
+// It supports a subset of JSONPath (see Evaluate) and JSON Schema (see Filter and the jsonschema package), claim formats and submission requirements.
+// Definitions using features it doesn't support can't be evaluated (see ErrUnsupported), instead of being evaluated partially.
+package pe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/jsonschema"
+)
+
+// ErrUnsupported is returned when a presentation definition can't be evaluated, because it uses features nuts-admin doesn't support or is invalid.
+var ErrUnsupported = errors.New("unable to evaluate presentation definition")
+
+// PresentationDefinition describes the credentials a verifier requires.
+type PresentationDefinition struct {
+	ID      string                 `json:"id"`
+	Name    string                 `json:"name,omitempty"`
+	Purpose string                 `json:"purpose,omitempty"`
+	Format  map[string]interface{} `json:"format,omitempty"`
+	// SubmissionRequirements select which input descriptors must be satisfied. Without them, all input descriptors must be.
+	SubmissionRequirements []SubmissionRequirement `json:"submission_requirements,omitempty"`
+	InputDescriptors       []InputDescriptor       `json:"input_descriptors"`
+}
+
+// InputDescriptor describes a single credential that is required.
+type InputDescriptor struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Purpose string   `json:"purpose,omitempty"`
+	Group   []string `json:"group,omitempty"`
+	// Format restricts the formats of the credential. If not set, the format of the presentation definition applies.
+	Format      map[string]interface{} `json:"format,omitempty"`
+	Constraints *Constraints           `json:"constraints,omitempty"`
+}
+
+// Constraints contains the fields a credential must contain to match an input descriptor.
+// The other constraints are only parsed to detect definitions that can't be evaluated (see PresentationDefinition.Check).
+type Constraints struct {
+	Fields          []Field                `json:"fields,omitempty"`
+	LimitDisclosure string                 `json:"limit_disclosure,omitempty"`
+	SubjectIsIssuer string                 `json:"subject_is_issuer,omitempty"`
+	IsHolder        []interface{}          `json:"is_holder,omitempty"`
+	SameSubject     []interface{}          `json:"same_subject,omitempty"`
+	Statuses        map[string]interface{} `json:"statuses,omitempty"`
+}
+
+// Field selects a value from a credential using JSONPath, which must match the filter (if any).
+type Field struct {
+	ID       string   `json:"id,omitempty"`
+	Path     []string `json:"path"`
+	Purpose  string   `json:"purpose,omitempty"`
+	Optional bool     `json:"optional,omitempty"`
+	Filter   Filter   `json:"filter,omitempty"`
+	// raw is the field as it occurs in the presentation definition, including the properties that aren't parsed.
+	raw map[string]interface{}
+}
+
+// UnmarshalJSON parses the field, keeping the field as it occurs in the presentation definition for reporting it (see DescriptorMatch.Missing).
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type alias Field
+	if err := json.Unmarshal(data, (*alias)(f)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &f.raw)
+}
+
+// ParsePresentationDefinition converts a presentation definition as returned by the Nuts node API.
+func ParsePresentationDefinition(definition map[string]interface{}) (*PresentationDefinition, error) {
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	var result PresentationDefinition
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Check returns an error wrapping ErrUnsupported if the presentation definition can't be evaluated.
+func (d PresentationDefinition) Check() error {
+	var errs []error
+	for _, descriptor := range d.InputDescriptors {
+		if err := descriptor.check(); err != nil {
+			errs = append(errs, fmt.Errorf("input descriptor %s: %w", descriptor.ID, err))
+		}
+	}
+	for i, requirement := range d.SubmissionRequirements {
+		if err := requirement.check(d.InputDescriptors); err != nil {
+			errs = append(errs, fmt.Errorf("submission requirement %d: %w", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrUnsupported, errors.Join(errs...))
+	}
+	return nil
+}
+
+func (d InputDescriptor) check() error {
+	if d.Constraints == nil {
+		return nil
+	}
+	switch {
+	case d.Constraints.LimitDisclosure == "required":
+		return errors.New("limit_disclosure required is not supported")
+	case d.Constraints.SubjectIsIssuer == "required":
+		return errors.New("subject_is_issuer required is not supported")
+	case len(d.Constraints.IsHolder) > 0:
+		return errors.New("is_holder is not supported")
+	case len(d.Constraints.SameSubject) > 0:
+		return errors.New("same_subject is not supported")
+	case len(d.Constraints.Statuses) > 0:
+		return errors.New("statuses is not supported")
+	}
+	for _, field := range d.Constraints.Fields {
+		for _, path := range field.Path {
+			if err := ValidatePath(path); err != nil {
+				return err
+			}
+		}
+		if field.Filter != nil {
+			if _, err := jsonschema.Compile(field.Filter); err != nil {
+				return fmt.Errorf("filter of field %v: %w", field.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluation is the result of matching credentials against a presentation definition.
+type Evaluation struct {
+	// Satisfied indicates whether the credentials satisfy the submission requirements,
+	// or if there are none, whether every input descriptor is matched by at least one credential.
+	Satisfied   bool              `json:"satisfied"`
+	Descriptors []DescriptorMatch `json:"input_descriptors"`
+	// Requirements contains the evaluation of the submission requirements, if any.
+	Requirements []RequirementMatch `json:"submission_requirements,omitempty"`
+}
+
+// DescriptorMatch lists the credentials that match an input descriptor.
+type DescriptorMatch struct {
+	ID string `json:"id"`
+	// Credentials contains the IDs (or, if a credential has no ID, the index) of the matching credentials.
+	Credentials []string `json:"credentials"`
+	// Values contains the values of the fields with an ID, taken from the first matching credential.
+	Values map[string]interface{} `json:"values,omitempty"`
+	// Missing contains the required fields the closest credential (the one that satisfies the most fields) doesn't satisfy,
+	// or all required fields if there are no credentials, as they occur in the presentation definition. It's only set if no credential matches.
+	Missing []map[string]interface{} `json:"missing,omitempty"`
+}
+
+// Match evaluates which credentials match the input descriptors of the presentation definition,
+// and whether they satisfy its submission requirements. A credential only matches an input descriptor if its format is allowed (see matchesFormat).
+// It returns an error wrapping ErrUnsupported if the presentation definition can't be evaluated (see Check).
+func (d PresentationDefinition) Match(credentials []vc.VerifiableCredential) (*Evaluation, error) {
+	if err := d.Check(); err != nil {
+		return nil, err
+	}
+	documents := make([]interface{}, len(credentials))
+	for i, credential := range credentials {
+		document, err := credentialDocument(credential)
+		if err != nil {
+			return nil, err
+		}
+		documents[i] = document
+	}
+	result := Evaluation{Descriptors: make([]DescriptorMatch, 0)}
+	matched := make([]bool, len(d.InputDescriptors))
+	for index, descriptor := range d.InputDescriptors {
+		match := DescriptorMatch{ID: descriptor.ID, Credentials: make([]string, 0)}
+		match.Missing = descriptor.requiredFields()
+		format := descriptor.Format
+		if len(format) == 0 {
+			format = d.Format
+		}
+		for i, document := range documents {
+			if !matchesFormat(format, credentials[i]) {
+				continue
+			}
+			values, missing, err := descriptor.match(document)
+			if err != nil {
+				return nil, fmt.Errorf("%w: input descriptor %s: %w", ErrUnsupported, descriptor.ID, err)
+			}
+			if len(missing) > 0 {
+				if len(match.Credentials) == 0 && len(missing) < len(match.Missing) {
+					match.Missing = missing
+				}
+				continue
+			}
+			if len(match.Credentials) == 0 && len(values) > 0 {
+				match.Values = values
+			}
+			match.Credentials = append(match.Credentials, credentialReference(credentials[i], i))
+		}
+		if len(match.Credentials) > 0 {
+			matched[index] = true
+			match.Missing = nil
+		}
+		result.Descriptors = append(result.Descriptors, match)
+	}
+	if len(d.SubmissionRequirements) == 0 {
+		result.Satisfied = !slices.Contains(matched, false)
+		return &result, nil
+	}
+	result.Satisfied = true
+	for _, requirement := range d.SubmissionRequirements {
+		requirementMatch := requirement.evaluate(d.InputDescriptors, matched)
+		result.Satisfied = result.Satisfied && requirementMatch.Satisfied
+		result.Requirements = append(result.Requirements, requirementMatch)
+	}
+	return &result, nil
+}
+
+// match returns the values of the fields that have an ID, and the required fields the credential doesn't satisfy.
+// The credential matches the input descriptor if no fields are missing.
+func (d InputDescriptor) match(document interface{}) (map[string]interface{}, []map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if d.Constraints == nil {
+		return values, nil, nil
+	}
+	var missing []map[string]interface{}
+	for _, field := range d.Constraints.Fields {
+		value, ok, err := field.match(document)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			if !field.Optional {
+				missing = append(missing, field.raw)
+			}
+			continue
+		}
+		if field.ID != "" {
+			values[field.ID] = value
+		}
+	}
+	return values, missing, nil
+}
+
+func (d InputDescriptor) requiredFields() []map[string]interface{} {
+	var result []map[string]interface{}
+	if d.Constraints != nil {
+		for _, field := range d.Constraints.Fields {
+			if !field.Optional {
+				result = append(result, field.raw)
+			}
+		}
+	}
+	return result
+}
+
+// match returns the first value selected by the paths of the field that matches the filter.
+func (f Field) match(document interface{}) (interface{}, bool, error) {
+	for _, path := range f.Path {
+		selected, err := Evaluate(path, document)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, value := range selected {
+			if f.Filter == nil {
+				return value, true, nil
+			}
+			matches, err := f.Filter.Matches(value)
+			if err != nil {
+				return nil, false, err
+			}
+			if matches {
+				return value, true, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// credentialDocument returns the credential as generic JSON document, as the JSONPath expressions of presentation definitions expect.
+// Credential subjects, statuses and proofs that occur once are represented as object instead of array (like JSON-LD credentials are usually written),
+// so the same definition applies to credentials in JWT format.
+func credentialDocument(credential vc.VerifiableCredential) (interface{}, error) {
+	type alias vc.VerifiableCredential
+	data, err := json.Marshal(alias(credential))
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"credentialSubject", "credentialStatus", "proof"} {
+		if values, ok := document[key].([]interface{}); ok && len(values) == 1 {
+			document[key] = values[0]
+		}
+	}
+	return document, nil
+}
+
+func credentialReference(credential vc.VerifiableCredential, index int) string {
+	if credential.ID != nil {
+		return credential.ID.String()
+	}
+	return "#" + strconv.Itoa(index)
+}
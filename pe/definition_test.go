@@ -0,0 +1,260 @@
+package pe
+
+import (
+	"encoding/base64"
+	"testing"
+
+	ssi "github.com/nuts-foundation/go-did"
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresentationDefinition_Match(t *testing.T) {
+	organizationCredential, err := vc.ParseVerifiableCredential(`{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"id": "did:web:issuer#1",
+		"type": ["VerifiableCredential", "NutsOrganizationCredential"],
+		"issuer": "did:web:issuer",
+		"issuanceDate": "2024-01-01T00:00:00Z",
+		"credentialSubject": {"id": "did:web:holder", "organization": {"name": "Hospital X", "city": "Amsterdam"}}
+	}`)
+	require.NoError(t, err)
+	definition, err := ParsePresentationDefinition(map[string]interface{}{
+		"id": "pd",
+		"input_descriptors": []interface{}{
+			map[string]interface{}{
+				"id": "organization",
+				"constraints": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{
+							"path":    []interface{}{"$.type"},
+							"purpose": "The credential must be an organization credential",
+							"filter":  map[string]interface{}{"type": "string", "const": "NutsOrganizationCredential", "format": "uri"},
+						},
+						map[string]interface{}{
+							"id":   "organization_name",
+							"path": []interface{}{"$.credentialSubject.organization.name", "$.credentialSubject[0].organization.name"},
+						},
+						map[string]interface{}{
+							"path":     []interface{}{"$.credentialSubject.organization.ura"},
+							"optional": true,
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("satisfied", func(t *testing.T) {
+		result, err := definition.Match([]vc.VerifiableCredential{*organizationCredential})
+
+		require.NoError(t, err)
+		assert.True(t, result.Satisfied)
+		require.Len(t, result.Descriptors, 1)
+		assert.Equal(t, []string{"did:web:issuer#1"}, result.Descriptors[0].Credentials)
+		assert.Equal(t, map[string]interface{}{"organization_name": "Hospital X"}, result.Descriptors[0].Values)
+		assert.Empty(t, result.Descriptors[0].Missing)
+	})
+	t.Run("not satisfied", func(t *testing.T) {
+		other := *organizationCredential
+		other.ID = nil
+		other.Type = []ssi.URI{vc.VerifiableCredentialTypeV1URI()}
+
+		result, err := definition.Match([]vc.VerifiableCredential{other})
+
+		require.NoError(t, err)
+		assert.False(t, result.Satisfied)
+		assert.Empty(t, result.Descriptors[0].Credentials)
+		require.Len(t, result.Descriptors[0].Missing, 1)
+		assert.Equal(t, map[string]interface{}{
+			"path":    []interface{}{"$.type"},
+			"purpose": "The credential must be an organization credential",
+			"filter":  map[string]interface{}{"type": "string", "const": "NutsOrganizationCredential", "format": "uri"},
+		}, result.Descriptors[0].Missing[0])
+	})
+	t.Run("no credentials", func(t *testing.T) {
+		result, err := definition.Match(nil)
+
+		require.NoError(t, err)
+		assert.False(t, result.Satisfied)
+		assert.Len(t, result.Descriptors[0].Missing, 2)
+	})
+}
+
+func TestPresentationDefinition_Match_submissionRequirements(t *testing.T) {
+	credential, err := vc.ParseVerifiableCredential(`{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"type": ["VerifiableCredential", "NutsUraCredential"],
+		"issuer": "did:web:issuer",
+		"issuanceDate": "2024-01-01T00:00:00Z",
+		"credentialSubject": {"id": "did:web:holder"}
+	}`)
+	require.NoError(t, err)
+	descriptor := func(id string, credentialType string, group string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":    id,
+			"group": []interface{}{group},
+			"constraints": map[string]interface{}{
+				"fields": []interface{}{map[string]interface{}{"path": []interface{}{"$.type"}, "filter": map[string]interface{}{"const": credentialType}}},
+			},
+		}
+	}
+	match := func(t *testing.T, requirements ...interface{}) *Evaluation {
+		definition, err := ParsePresentationDefinition(map[string]interface{}{
+			"id":                      "pd",
+			"submission_requirements": requirements,
+			"input_descriptors": []interface{}{
+				descriptor("ura", "NutsUraCredential", "A"),
+				descriptor("agb", "NutsAgbCredential", "A"),
+				descriptor("organization", "NutsOrganizationCredential", "B"),
+			},
+		})
+		require.NoError(t, err)
+		result, err := definition.Match([]vc.VerifiableCredential{*credential})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("pick one of group", func(t *testing.T) {
+		result := match(t, map[string]interface{}{"rule": "pick", "count": 1, "from": "A"})
+
+		assert.True(t, result.Satisfied)
+		require.Len(t, result.Requirements, 1)
+		assert.Equal(t, 1, result.Requirements[0].Matched)
+		assert.Equal(t, 2, result.Requirements[0].Total)
+	})
+	t.Run("all of group", func(t *testing.T) {
+		result := match(t, map[string]interface{}{"rule": "all", "from": "A"})
+
+		assert.False(t, result.Satisfied)
+	})
+	t.Run("pick min from nested", func(t *testing.T) {
+		result := match(t, map[string]interface{}{"rule": "pick", "min": 1, "from_nested": []interface{}{
+			map[string]interface{}{"rule": "all", "from": "A"},
+			map[string]interface{}{"rule": "pick", "count": 1, "from": "A"},
+		}})
+
+		assert.True(t, result.Satisfied)
+		require.Len(t, result.Requirements[0].Nested, 2)
+		assert.False(t, result.Requirements[0].Nested[0].Satisfied)
+	})
+	t.Run("every requirement must be satisfied", func(t *testing.T) {
+		result := match(t,
+			map[string]interface{}{"rule": "pick", "count": 1, "from": "A"},
+			map[string]interface{}{"rule": "all", "from": "B"},
+		)
+
+		assert.False(t, result.Satisfied)
+	})
+}
+
+func TestPresentationDefinition_Match_format(t *testing.T) {
+	ldpCredential, err := vc.ParseVerifiableCredential(`{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"type": ["VerifiableCredential", "NutsUraCredential"],
+		"issuer": "did:web:issuer",
+		"issuanceDate": "2024-01-01T00:00:00Z",
+		"credentialSubject": {"id": "did:web:holder"},
+		"proof": {"type": "JsonWebSignature2020"}
+	}`)
+	require.NoError(t, err)
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES384","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"did:web:issuer","sub":"did:web:holder","nbf":1704067200,` +
+		`"vc":{"@context":["https://www.w3.org/2018/credentials/v1"],"type":["VerifiableCredential","NutsUraCredential"],"credentialSubject":{}}}`))
+	jwtCredential, err := vc.ParseVerifiableCredential(header + "." + payload + ".c2lnbmF0dXJl")
+	require.NoError(t, err)
+	credentials := []vc.VerifiableCredential{*ldpCredential, *jwtCredential}
+	match := func(t *testing.T, format map[string]interface{}, descriptorFormat map[string]interface{}) []string {
+		descriptor := map[string]interface{}{"id": "ura"}
+		if descriptorFormat != nil {
+			descriptor["format"] = descriptorFormat
+		}
+		definition, err := ParsePresentationDefinition(map[string]interface{}{
+			"id":                "pd",
+			"format":            format,
+			"input_descriptors": []interface{}{descriptor},
+		})
+		require.NoError(t, err)
+		result, err := definition.Match(credentials)
+		require.NoError(t, err)
+		return result.Descriptors[0].Credentials
+	}
+
+	t.Run("no format", func(t *testing.T) {
+		assert.Equal(t, []string{"#0", "#1"}, match(t, nil, nil))
+	})
+	t.Run("proof type", func(t *testing.T) {
+		format := map[string]interface{}{
+			"ldp_vc": map[string]interface{}{"proof_type": []interface{}{"JsonWebSignature2020"}},
+			"jwt_vc": map[string]interface{}{"alg": []interface{}{"ES256"}},
+		}
+
+		assert.Equal(t, []string{"#0"}, match(t, format, nil))
+	})
+	t.Run("algorithm", func(t *testing.T) {
+		format := map[string]interface{}{"jwt_vc": map[string]interface{}{"alg": []interface{}{"ES256", "ES384"}}}
+
+		assert.Equal(t, []string{"#1"}, match(t, format, nil))
+	})
+	t.Run("only presentation formats", func(t *testing.T) {
+		assert.Empty(t, match(t, map[string]interface{}{"jwt_vp": map[string]interface{}{}}, nil))
+	})
+	t.Run("input descriptor format takes precedence", func(t *testing.T) {
+		format := map[string]interface{}{"jwt_vc": map[string]interface{}{}}
+
+		assert.Equal(t, []string{"#0"}, match(t, format, map[string]interface{}{"ldp": map[string]interface{}{}}))
+	})
+}
+
+func TestPresentationDefinition_Check(t *testing.T) {
+	testCases := map[string]struct {
+		definition map[string]interface{}
+		err        string
+	}{
+		"unsupported filter keyword": {
+			definition: map[string]interface{}{"input_descriptors": []interface{}{map[string]interface{}{"id": "1", "constraints": map[string]interface{}{
+				"fields": []interface{}{map[string]interface{}{"path": []interface{}{"$.type"}, "filter": map[string]interface{}{"anyOf": []interface{}{}}}},
+			}}}},
+			err: "input descriptor 1: filter of field [$.type]: invalid schema: $.anyOf: unsupported keyword",
+		},
+		"unsupported JSONPath": {
+			definition: map[string]interface{}{"input_descriptors": []interface{}{map[string]interface{}{"id": "1", "constraints": map[string]interface{}{
+				"fields": []interface{}{map[string]interface{}{"path": []interface{}{"$..type"}}},
+			}}}},
+			err: `input descriptor 1: JSONPath is not supported by nuts-admin: "$..type" uses recursive descent (..)`,
+		},
+		"unsupported constraint": {
+			definition: map[string]interface{}{"input_descriptors": []interface{}{map[string]interface{}{"id": "1", "constraints": map[string]interface{}{
+				"subject_is_issuer": "required",
+			}}}},
+			err: "input descriptor 1: subject_is_issuer required is not supported",
+		},
+		"unknown group": {
+			definition: map[string]interface{}{
+				"submission_requirements": []interface{}{map[string]interface{}{"rule": "all", "from": "A"}},
+				"input_descriptors":       []interface{}{map[string]interface{}{"id": "1"}},
+			},
+			err: `submission requirement 0: no input descriptor is in group "A"`,
+		},
+		"unknown rule": {
+			definition: map[string]interface{}{
+				"submission_requirements": []interface{}{map[string]interface{}{"rule": "any", "from": "A"}},
+				"input_descriptors":       []interface{}{map[string]interface{}{"id": "1", "group": []interface{}{"A"}}},
+			},
+			err: `submission requirement 0: unknown rule "any"`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			definition, err := ParsePresentationDefinition(testCase.definition)
+			require.NoError(t, err)
+
+			_, err = definition.Match(nil)
+
+			assert.ErrorIs(t, err, ErrUnsupported)
+			assert.ErrorContains(t, err, testCase.err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package pe
+
+import (
+	"github.com/nuts-foundation/nuts-admin/jsonschema"
+)
+
+// Filter is a JSON Schema that a value selected by a field must match, as it occurs in the presentation definition.
+// It's evaluated by the jsonschema package, so filters using keywords it doesn't support result in an error instead of a guess.
+type Filter map[string]interface{}
+
+// Matches returns whether the value matches the filter.
+// If the value is an array that doesn't match, it matches if any of the items matches,
+// since paths like $.type select the array of credential types.
+func (f Filter) Matches(value interface{}) (bool, error) {
+	schema, err := jsonschema.Compile(f)
+	if err != nil {
+		return false, err
+	}
+	if schema.Validate(value) == nil {
+		return true, nil
+	}
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if schema.Validate(item) == nil {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
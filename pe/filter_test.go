@@ -0,0 +1,57 @@
+package pe
+
+import (
+	"testing"
+
+	"github.com/nuts-foundation/nuts-admin/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   Filter
+		value    interface{}
+		expected bool
+	}{
+		{"type string", Filter{"type": "string"}, "x", true},
+		{"type mismatch", Filter{"type": "string"}, 1.0, false},
+		{"type integer", Filter{"type": "integer"}, 2.0, true},
+		{"type integer with fraction", Filter{"type": "integer"}, 2.5, false},
+		{"const", Filter{"type": "string", "const": "NutsUraCredential"}, "NutsUraCredential", true},
+		{"const mismatch", Filter{"const": "NutsUraCredential"}, "NutsOrganizationCredential", false},
+		{"const number", Filter{"const": 1}, 1.0, true},
+		{"const null", Filter{"const": nil}, "x", false},
+		{"const null matches null", Filter{"const": nil}, nil, true},
+		{"enum", Filter{"enum": []interface{}{"a", "b"}}, "b", true},
+		{"enum mismatch", Filter{"enum": []interface{}{"a", "b"}}, "c", false},
+		{"pattern", Filter{"type": "string", "pattern": "^did:web:"}, "did:web:example.com", true},
+		{"pattern mismatch", Filter{"pattern": "^did:web:"}, "did:nuts:123", false},
+		{"minimum", Filter{"type": "number", "minimum": 18}, 17.0, false},
+		{"array item matches", Filter{"type": "string", "const": "NutsUraCredential"}, []interface{}{"VerifiableCredential", "NutsUraCredential"}, true},
+		{"no array item matches", Filter{"const": "NutsUraCredential"}, []interface{}{"VerifiableCredential"}, false},
+		{"type array", Filter{"type": "array"}, []interface{}{"a"}, true},
+		{"contains", Filter{"type": "array", "contains": map[string]interface{}{"const": "a"}}, []interface{}{"b", "a"}, true},
+		{"contains mismatch", Filter{"type": "array", "contains": map[string]interface{}{"const": "a"}}, []interface{}{"b"}, false},
+		{"no constraints", Filter{}, map[string]interface{}{}, true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			matches, err := testCase.filter.Matches(testCase.value)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, matches)
+		})
+	}
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := Filter{"pattern": "("}.Matches("x")
+
+		assert.ErrorContains(t, err, "invalid schema: $.pattern")
+	})
+	t.Run("unsupported keyword", func(t *testing.T) {
+		_, err := Filter{"oneOf": []interface{}{}}.Matches("x")
+
+		assert.ErrorIs(t, err, jsonschema.ErrUnsupportedKeyword)
+	})
+}
@@ -0,0 +1,66 @@
+package pe
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/nuts-foundation/go-did/vc"
+)
+
+// matchesFormat returns whether the format designation (e.g. {"jwt_vc": {"alg": ["ES256"]}}) allows the credential.
+// Without a format designation, any credential is allowed. Otherwise the credential's format (jwt_vc or ldp_vc, or the generic jwt or ldp) must be listed,
+// and if the designation lists algorithms or proof types, the credential's JWT algorithm or one of its proof types must be among them.
+func matchesFormat(format map[string]interface{}, credential vc.VerifiableCredential) bool {
+	if len(format) == 0 {
+		return true
+	}
+	names := []string{vc.JSONLDCredentialProofFormat, "ldp"}
+	property := "proof_type"
+	if credential.Format() == vc.JWTCredentialProofFormat {
+		names = []string{vc.JWTCredentialProofFormat, "jwt"}
+		property = "alg"
+	}
+	for _, name := range names {
+		designation, ok := format[name]
+		if !ok {
+			continue
+		}
+		properties, _ := designation.(map[string]interface{})
+		allowed, ok := properties[property].([]interface{})
+		if !ok {
+			return true
+		}
+		for _, value := range credentialAlgorithms(credential) {
+			if slices.Contains(allowed, interface{}(value)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// credentialAlgorithms returns the JWT algorithm of a JWT credential, or the proof types of a JSON-LD credential.
+func credentialAlgorithms(credential vc.VerifiableCredential) []string {
+	if credential.Format() == vc.JWTCredentialProofFormat {
+		header, _, _ := strings.Cut(credential.Raw(), ".")
+		data, err := base64.RawURLEncoding.DecodeString(header)
+		if err != nil {
+			return nil
+		}
+		var decoded struct {
+			Algorithm string `json:"alg"`
+		}
+		if json.Unmarshal(data, &decoded) != nil {
+			return nil
+		}
+		return []string{decoded.Algorithm}
+	}
+	proofs, _ := credential.Proofs()
+	var result []string
+	for _, proof := range proofs {
+		result = append(result, string(proof.Type))
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package pe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedPath is returned for valid JSONPath expressions that use features Evaluate doesn't support, e.g. recursive descent or filter expressions.
+var ErrUnsupportedPath = errors.New("JSONPath is not supported by nuts-admin")
+
+// Evaluate returns the values in the document selected by the JSONPath expression.
+// Only the subset of JSONPath that is used in presentation definitions is supported:
+// the root ($), child members (.name, ['name'] or ["name"]), array indices ([0]) and wildcards (.* or [*]).
+func Evaluate(path string, document interface{}) ([]interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	current := []interface{}{document}
+	for _, segment := range segments {
+		var next []interface{}
+		for _, value := range current {
+			next = append(next, segment.apply(value)...)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+type pathSegment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func (s pathSegment) apply(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			result := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				result = append(result, child)
+			}
+			return result
+		}
+		if child, ok := v[s.name]; ok && !s.isIndex {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		if s.isIndex && s.index >= 0 && s.index < len(v) {
+			return []interface{}{v[s.index]}
+		}
+	}
+	return nil
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+	var result []pathSegment
+	remainder := path[1:]
+	for remainder != "" {
+		switch remainder[0] {
+		case '.':
+			remainder = remainder[1:]
+			if strings.HasPrefix(remainder, ".") {
+				return nil, fmt.Errorf("%w: %q uses recursive descent (..)", ErrUnsupportedPath, path)
+			}
+			end := strings.IndexAny(remainder, ".[")
+			if end == -1 {
+				end = len(remainder)
+			}
+			name := remainder[:end]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name", path)
+			}
+			result = append(result, pathSegment{name: name, wildcard: name == "*"})
+			remainder = remainder[end:]
+		case '[':
+			end := strings.Index(remainder, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath %q: missing ]", path)
+			}
+			segment, err := parseBracket(path, remainder[1:end])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, segment)
+			remainder = remainder[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, remainder[0])
+		}
+	}
+	return result, nil
+}
+
+func parseBracket(path string, contents string) (pathSegment, error) {
+	contents = strings.TrimSpace(contents)
+	if contents == "*" {
+		return pathSegment{wildcard: true}, nil
+	}
+	if len(contents) >= 2 && (contents[0] == '\'' || contents[0] == '"') && contents[len(contents)-1] == contents[0] {
+		return pathSegment{name: contents[1 : len(contents)-1]}, nil
+	}
+	index, err := strconv.Atoi(contents)
+	if err != nil {
+		// Filter expressions, slices and unions are valid JSONPath
+		if strings.HasPrefix(contents, "?") || strings.ContainsAny(contents, ":,") {
+			return pathSegment{}, fmt.Errorf("%w: %q uses expression [%s]", ErrUnsupportedPath, path, contents)
+		}
+		return pathSegment{}, fmt.Errorf("invalid JSONPath %q: invalid expression [%s]", path, contents)
+	}
+	return pathSegment{index: index, isIndex: true}, nil
+}
+
+// ValidatePath returns an error if the JSONPath expression is invalid, or an error wrapping ErrUnsupportedPath if it uses features Evaluate doesn't support.
+func ValidatePath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
@@ -0,0 +1,61 @@
+package pe
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	var document interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": ["VerifiableCredential", "NutsOrganizationCredential"],
+		"credentialSubject": {"organization": {"name": "Hospital X", "city": "Amsterdam"}}
+	}`), &document))
+
+	testCases := []struct {
+		path     string
+		expected []interface{}
+	}{
+		{"$", []interface{}{document}},
+		{"$.type", []interface{}{[]interface{}{"VerifiableCredential", "NutsOrganizationCredential"}}},
+		{"$.type[1]", []interface{}{"NutsOrganizationCredential"}},
+		{"$.type[*]", []interface{}{"VerifiableCredential", "NutsOrganizationCredential"}},
+		{"$.credentialSubject.organization.name", []interface{}{"Hospital X"}},
+		{"$['credentialSubject'][\"organization\"]['city']", []interface{}{"Amsterdam"}},
+		{"$.credentialSubject.organization.unknown", nil},
+		{"$.type[5]", nil},
+		{"$.type.name", nil},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			result, err := Evaluate(testCase.path, document)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+	t.Run("wildcard on object", func(t *testing.T) {
+		result, err := Evaluate("$.credentialSubject.organization.*", document)
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []interface{}{"Hospital X", "Amsterdam"}, result)
+	})
+	t.Run("invalid paths", func(t *testing.T) {
+		for _, path := range []string{"credentialSubject", "$.", "$.type[", "$.type[x]", "$x"} {
+			_, err := Evaluate(path, document)
+
+			assert.Error(t, err, path)
+			assert.NotErrorIs(t, err, ErrUnsupportedPath, path)
+		}
+	})
+	t.Run("unsupported paths", func(t *testing.T) {
+		for _, path := range []string{"$..name", "$.credentialSubject[?(@.type=='x')]", "$.type[0:1]", "$.type[0,1]"} {
+			_, err := Evaluate(path, document)
+
+			assert.ErrorIs(t, err, ErrUnsupportedPath, path)
+		}
+	})
+}
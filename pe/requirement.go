@@ -0,0 +1,86 @@
+package pe
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// SubmissionRequirement selects the input descriptors of a group, or nested submission requirements, that must be satisfied.
+type SubmissionRequirement struct {
+	Name    string `json:"name,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+	// Rule is either all or pick.
+	Rule       string                  `json:"rule"`
+	Count      *int                    `json:"count,omitempty"`
+	Min        *int                    `json:"min,omitempty"`
+	Max        *int                    `json:"max,omitempty"`
+	From       string                  `json:"from,omitempty"`
+	FromNested []SubmissionRequirement `json:"from_nested,omitempty"`
+}
+
+// RequirementMatch describes whether a submission requirement is satisfied.
+type RequirementMatch struct {
+	Name      string `json:"name,omitempty"`
+	Rule      string `json:"rule"`
+	From      string `json:"from,omitempty"`
+	Satisfied bool   `json:"satisfied"`
+	// Matched is the number of input descriptors of the group, or nested requirements, that are satisfied.
+	Matched int `json:"matched"`
+	// Total is the number of input descriptors of the group, or nested requirements.
+	Total  int                `json:"total"`
+	Nested []RequirementMatch `json:"from_nested,omitempty"`
+}
+
+func (r SubmissionRequirement) check(descriptors []InputDescriptor) error {
+	if r.Rule != "all" && r.Rule != "pick" {
+		return fmt.Errorf("unknown rule %q", r.Rule)
+	}
+	if (r.From == "") == (len(r.FromNested) == 0) {
+		return errors.New("requires either from or from_nested")
+	}
+	if r.From != "" && !slices.ContainsFunc(descriptors, func(descriptor InputDescriptor) bool { return slices.Contains(descriptor.Group, r.From) }) {
+		return fmt.Errorf("no input descriptor is in group %q", r.From)
+	}
+	for i, nested := range r.FromNested {
+		if err := nested.check(descriptors); err != nil {
+			return fmt.Errorf("from_nested %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// evaluate returns whether the requirement is satisfied, given which input descriptors are matched by a credential.
+// A pick rule is satisfied if enough input descriptors (or nested requirements) are satisfied to pick count or min of them:
+// more isn't a problem, since the holder can leave credentials out of the presentation.
+func (r SubmissionRequirement) evaluate(descriptors []InputDescriptor, matched []bool) RequirementMatch {
+	result := RequirementMatch{Name: r.Name, Rule: r.Rule, From: r.From}
+	if r.From != "" {
+		for i, descriptor := range descriptors {
+			if slices.Contains(descriptor.Group, r.From) {
+				result.Total++
+				if matched[i] {
+					result.Matched++
+				}
+			}
+		}
+	} else {
+		for _, nested := range r.FromNested {
+			nestedMatch := nested.evaluate(descriptors, matched)
+			result.Total++
+			if nestedMatch.Satisfied {
+				result.Matched++
+			}
+			result.Nested = append(result.Nested, nestedMatch)
+		}
+	}
+	switch {
+	case r.Rule == "all":
+		result.Satisfied = result.Matched == result.Total
+	case r.Count != nil:
+		result.Satisfied = result.Matched >= *r.Count
+	default:
+		result.Satisfied = r.Min == nil || result.Matched >= *r.Min
+	}
+	return result
+}
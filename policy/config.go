@@ -0,0 +1,25 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+)
+
+type Config struct {
+	// Directory contains the policy files of the Nuts node (its policy.directory), e.g. mounted read-only.
+	Directory string `koanf:"directory"`
+}
+
+func (c Config) Validate() error {
+	if c.Directory == "" {
+		return nil
+	}
+	info, err := os.Stat(c.Directory)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", c.Directory)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package policy evaluates the presentation definitions of the Nuts node's policy against the wallets of subjects,
+// so admins know whether a subject can satisfy a scope before a use case is enabled.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/pe"
+)
+
+// OrganizationWalletOwner is the wallet owner type of the presentation definition that is evaluated against the subject's wallet.
+const OrganizationWalletOwner = "organization"
+
+// ErrInvalidPolicy is returned when a policy file can't be parsed.
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// ErrScopeNotFound is returned when the policy doesn't contain the scope.
+var ErrScopeNotFound = errors.New("scope not found")
+
+// Policy maps scopes to a presentation definition per wallet owner type (organization or user), like the Nuts node's policy files.
+type Policy map[string]map[string]map[string]interface{}
+
+// Scopes returns the scopes of the policy, sorted.
+func (p Policy) Scopes() []string {
+	result := make([]string, 0, len(p))
+	for scope := range p {
+		result = append(result, scope)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Parse parses a policy file.
+func Parse(data []byte) (Policy, error) {
+	var result Policy
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	return result, nil
+}
+
+type Service struct {
+	Config          Config
+	IdentityService identity.Service
+}
+
+// EvaluationRequest selects the scope and subject to evaluate.
+type EvaluationRequest struct {
+	Scope   string `json:"scope"`
+	Subject string `json:"subject"`
+	// Policy is an uploaded policy file. If not set, the policy files of the Nuts node are used.
+	Policy Policy `json:"policy,omitempty"`
+}
+
+// Evaluation is the result of evaluating the organization presentation definition of a scope against a subject's wallet.
+type Evaluation struct {
+	Scope                    string `json:"scope"`
+	Subject                  string `json:"subject"`
+	PresentationDefinitionID string `json:"presentation_definition_id"`
+	pe.Evaluation
+	// Ignored contains the IDs of wallet credentials that weren't evaluated, because they're revoked or expired.
+	Ignored []string `json:"ignored"`
+}
+
+// Load reads the policy files from the configured directory. Like the Nuts node, a scope may only be defined once.
+func (s Service) Load() (Policy, error) {
+	result := Policy{}
+	// Without a directory, only uploaded policies can be evaluated
+	if s.Config.Directory == "" {
+		return result, nil
+	}
+	names, err := filepath.Glob(filepath.Join(s.Config.Directory, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		policy, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+		}
+		for scope, definitions := range policy {
+			if _, exists := result[scope]; exists {
+				return nil, fmt.Errorf("%w: %s: scope %q is defined more than once", ErrInvalidPolicy, filepath.Base(name), scope)
+			}
+			result[scope] = definitions
+		}
+	}
+	return result, nil
+}
+
+// Evaluate evaluates the organization presentation definition of the scope (including its submission requirements) against the active credentials
+// in the subject's wallet, and reports the requirements the wallet doesn't satisfy.
+// It returns an error wrapping pe.ErrUnsupported if the presentation definition can't be evaluated.
+func (s Service) Evaluate(ctx context.Context, request EvaluationRequest) (*Evaluation, error) {
+	policy := request.Policy
+	if policy == nil {
+		var err error
+		if policy, err = s.Load(); err != nil {
+			return nil, err
+		}
+	}
+	definitions, ok := policy[request.Scope]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrScopeNotFound, request.Scope)
+	}
+	definition, ok := definitions[OrganizationWalletOwner]
+	if !ok {
+		return nil, fmt.Errorf("%w: scope %q has no %s presentation definition", ErrInvalidPolicy, request.Scope, OrganizationWalletOwner)
+	}
+	presentationDefinition, err := pe.ParsePresentationDefinition(definition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	if err := presentationDefinition.Check(); err != nil {
+		return nil, err
+	}
+	walletCredentials, err := s.IdentityService.WalletCredentials(ctx, request.Subject)
+	if err != nil {
+		return nil, err
+	}
+	result := Evaluation{
+		Scope:                    request.Scope,
+		Subject:                  request.Subject,
+		PresentationDefinitionID: presentationDefinition.ID,
+		Ignored:                  make([]string, 0),
+	}
+	var credentials []vc.VerifiableCredential
+	for _, credential := range walletCredentials {
+		if credential.Status != "active" {
+			if credential.ID != nil {
+				result.Ignored = append(result.Ignored, credential.ID.String())
+			}
+			continue
+		}
+		credentials = append(credentials, vc.VerifiableCredential(credential.VerifiableCredential))
+	}
+	evaluation, err := presentationDefinition.Match(credentials)
+	if err != nil {
+		return nil, err
+	}
+	result.Evaluation = *evaluation
+	return &result, nil
+}
@@ -0,0 +1,70 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuts-foundation/nuts-admin/pe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Load(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		policy, err := Service{Config: Config{Directory: "../deploy/policy"}}.Load()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"test"}, policy.Scopes())
+		assert.Equal(t, "pd_any_care_organization_with_employee", policy["test"][OrganizationWalletOwner]["id"])
+	})
+	t.Run("no directory", func(t *testing.T) {
+		policy, err := Service{}.Load()
+
+		require.NoError(t, err)
+		assert.Empty(t, policy)
+	})
+	t.Run("scope defined twice", func(t *testing.T) {
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, "a.json"), []byte(`{"test": {}}`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(directory, "b.json"), []byte(`{"test": {}}`), 0600))
+
+		_, err := Service{Config: Config{Directory: directory}}.Load()
+
+		assert.ErrorIs(t, err, ErrInvalidPolicy)
+		assert.ErrorContains(t, err, `b.json: scope "test" is defined more than once`)
+	})
+	t.Run("invalid file", func(t *testing.T) {
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, "a.json"), []byte(`{"test": "pd"}`), 0600))
+
+		_, err := Service{Config: Config{Directory: directory}}.Load()
+
+		assert.ErrorIs(t, err, ErrInvalidPolicy)
+	})
+}
+
+func TestService_Evaluate(t *testing.T) {
+	t.Run("unknown scope", func(t *testing.T) {
+		_, err := Service{Config: Config{Directory: "../deploy/policy"}}.Evaluate(context.Background(), EvaluationRequest{Scope: "other", Subject: "hospital_x"})
+
+		assert.ErrorIs(t, err, ErrScopeNotFound)
+	})
+	t.Run("uploaded policy without organization presentation definition", func(t *testing.T) {
+		policy, err := Parse([]byte(`{"other": {"user": {"id": "pd"}}}`))
+		require.NoError(t, err)
+
+		_, err = Service{}.Evaluate(context.Background(), EvaluationRequest{Scope: "other", Subject: "hospital_x", Policy: policy})
+
+		assert.ErrorIs(t, err, ErrInvalidPolicy)
+	})
+	t.Run("presentation definition can't be evaluated", func(t *testing.T) {
+		policy, err := Parse([]byte(`{"other": {"organization": {"id": "pd", "input_descriptors": [{"id": "1", "constraints": {"is_holder": [{}]}}]}}}`))
+		require.NoError(t, err)
+
+		_, err = Service{}.Evaluate(context.Background(), EvaluationRequest{Scope: "other", Subject: "hospital_x", Policy: policy})
+
+		assert.ErrorIs(t, err, pe.ErrUnsupported)
+	})
+}
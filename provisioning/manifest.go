@@ -0,0 +1,175 @@
+package provisioning
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	ssi "github.com/nuts-foundation/go-did"
+)
+
+// defaultCredentialContext is the JSON-LD context used for self-issued credentials if none is specified.
+const defaultCredentialContext = "https://nuts.nl/credentials/v1"
+
+// defaultValidDays is the validity of self-issued credentials if none is specified.
+const defaultValidDays = 365
+
+// Manifest lists the subjects to provision.
+type Manifest struct {
+	Subjects []SubjectManifest `json:"subjects"`
+}
+
+// SubjectManifest describes a single subject to provision:
+// the subject is created, the credentials are self-issued and loaded into its wallet,
+// after which the discovery services are activated.
+type SubjectManifest struct {
+	Subject           string               `json:"subject"`
+	Credentials       []CredentialManifest `json:"credentials,omitempty"`
+	DiscoveryServices []string             `json:"discovery_services,omitempty"`
+}
+
+// CredentialManifest describes a credential the subject issues to itself.
+// The issuer and credentialSubject.id are set to the subject's DID.
+type CredentialManifest struct {
+	Context           string                 `json:"@context,omitempty"`
+	Type              string                 `json:"type"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+	// ValidDays is the number of days the credential is valid, defaults to 365.
+	ValidDays int `json:"valid_days,omitempty"`
+}
+
+// Validate checks the manifest for missing or duplicate subjects and credentials without a valid type.
+func (m Manifest) Validate() error {
+	if len(m.Subjects) == 0 {
+		return errors.New("manifest contains no subjects")
+	}
+	seen := map[string]bool{}
+	for i, subject := range m.Subjects {
+		if strings.TrimSpace(subject.Subject) == "" {
+			return fmt.Errorf("subject #%d: subject is required", i+1)
+		}
+		if seen[subject.Subject] {
+			return fmt.Errorf("subject #%d: duplicate subject: %s", i+1, subject.Subject)
+		}
+		seen[subject.Subject] = true
+		for j, credential := range subject.Credentials {
+			if strings.TrimSpace(credential.Type) == "" {
+				return fmt.Errorf("subject #%d (%s), credential #%d: type is required", i+1, subject.Subject, j+1)
+			}
+			if _, err := ssi.ParseURI(credential.Type); err != nil {
+				return fmt.Errorf("subject #%d (%s), credential #%d: invalid type: %w", i+1, subject.Subject, j+1, err)
+			}
+			if credential.ValidDays < 0 {
+				return fmt.Errorf("subject #%d (%s), credential #%d: valid_days can't be negative", i+1, subject.Subject, j+1)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseJSON parses a manifest in JSON format.
+func ParseJSON(reader io.Reader) (*Manifest, error) {
+	var result Manifest
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid JSON manifest: %w", err)
+	}
+	return &result, result.Validate()
+}
+
+// ParseCSV parses a manifest in CSV format. The first row must contain the column names:
+//   - subject (required): the subject to create.
+//   - discovery_services: semicolon-separated list of discovery services to activate.
+//   - <credential type>:<path>: a claim of a credential to self-issue, with a dot-separated path into the credential subject,
+//     e.g. NutsOrganizationCredential:organization.name.
+//
+// Rows without any claim for a credential type don't get that credential.
+func ParseCSV(reader io.Reader) (*Manifest, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV manifest: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("invalid CSV manifest: missing header row")
+	}
+	header := records[0]
+	subjectColumn := -1
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		header[i] = column
+		switch {
+		case column == "subject":
+			subjectColumn = i
+		case column == "discovery_services":
+		case strings.Contains(column, ":"):
+			credentialType, path, _ := strings.Cut(column, ":")
+			if credentialType == "" || path == "" {
+				return nil, fmt.Errorf("invalid CSV manifest: invalid credential column: %s", column)
+			}
+		default:
+			return nil, fmt.Errorf("invalid CSV manifest: unknown column: %s", column)
+		}
+	}
+	if subjectColumn == -1 {
+		return nil, errors.New("invalid CSV manifest: missing subject column")
+	}
+
+	var result Manifest
+	for _, record := range records[1:] {
+		subject := SubjectManifest{}
+		credentials := map[string]*CredentialManifest{}
+		var credentialTypes []string
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			switch column := header[i]; {
+			case column == "subject":
+				subject.Subject = value
+			case column == "discovery_services":
+				for _, serviceID := range strings.Split(value, ";") {
+					if serviceID = strings.TrimSpace(serviceID); serviceID != "" {
+						subject.DiscoveryServices = append(subject.DiscoveryServices, serviceID)
+					}
+				}
+			default:
+				if value == "" {
+					continue
+				}
+				credentialType, path, _ := strings.Cut(column, ":")
+				credential, ok := credentials[credentialType]
+				if !ok {
+					credential = &CredentialManifest{
+						Type:              credentialType,
+						CredentialSubject: map[string]interface{}{},
+					}
+					credentials[credentialType] = credential
+					credentialTypes = append(credentialTypes, credentialType)
+				}
+				setPath(credential.CredentialSubject, strings.Split(path, "."), value)
+			}
+		}
+		for _, credentialType := range credentialTypes {
+			subject.Credentials = append(subject.Credentials, *credentials[credentialType])
+		}
+		result.Subjects = append(result.Subjects, subject)
+	}
+	return &result, result.Validate()
+}
+
+func setPath(target map[string]interface{}, path []string, value string) {
+	if len(path) == 1 {
+		target[path[0]] = value
+		return
+	}
+	child, ok := target[path[0]].(map[string]interface{})
+	if !ok {
+		child = map[string]interface{}{}
+		target[path[0]] = child
+	}
+	setPath(child, path[1:], value)
+}
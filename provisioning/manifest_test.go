@@ -0,0 +1,76 @@
+package provisioning
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		input := `subject,discovery_services,NutsOrganizationCredential:organization.name,NutsOrganizationCredential:organization.city
+location_1,dev:eOverdracht2023;other,Hospital X,Amsterdam
+location_2,,,`
+		manifest, err := ParseCSV(strings.NewReader(input))
+
+		require.NoError(t, err)
+		require.Len(t, manifest.Subjects, 2)
+		assert.Equal(t, SubjectManifest{
+			Subject: "location_1",
+			Credentials: []CredentialManifest{
+				{
+					Type: "NutsOrganizationCredential",
+					CredentialSubject: map[string]interface{}{
+						"organization": map[string]interface{}{
+							"name": "Hospital X",
+							"city": "Amsterdam",
+						},
+					},
+				},
+			},
+			DiscoveryServices: []string{"dev:eOverdracht2023", "other"},
+		}, manifest.Subjects[0])
+		assert.Equal(t, SubjectManifest{Subject: "location_2"}, manifest.Subjects[1])
+	})
+	t.Run("missing subject column", func(t *testing.T) {
+		_, err := ParseCSV(strings.NewReader("discovery_services\nfoo"))
+		assert.EqualError(t, err, "invalid CSV manifest: missing subject column")
+	})
+	t.Run("unknown column", func(t *testing.T) {
+		_, err := ParseCSV(strings.NewReader("subject,name\nfoo,bar"))
+		assert.EqualError(t, err, "invalid CSV manifest: unknown column: name")
+	})
+	t.Run("duplicate subject", func(t *testing.T) {
+		_, err := ParseCSV(strings.NewReader("subject\nfoo\nfoo"))
+		assert.EqualError(t, err, "subject #2: duplicate subject: foo")
+	})
+	t.Run("no subjects", func(t *testing.T) {
+		_, err := ParseCSV(strings.NewReader("subject"))
+		assert.EqualError(t, err, "manifest contains no subjects")
+	})
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		input := `{"subjects": [{"subject": "location_1", "credentials": [{"type": "NutsOrganizationCredential", "credentialSubject": {"organization": {"name": "Hospital X"}}, "valid_days": 30}]}]}`
+		manifest, err := ParseJSON(strings.NewReader(input))
+
+		require.NoError(t, err)
+		require.Len(t, manifest.Subjects, 1)
+		assert.Equal(t, 30, manifest.Subjects[0].Credentials[0].ValidDays)
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := ParseJSON(strings.NewReader(`{"subjects": [{"name": "foo"}]}`))
+		assert.ErrorContains(t, err, "unknown field")
+	})
+	t.Run("credential without type", func(t *testing.T) {
+		_, err := ParseJSON(strings.NewReader(`{"subjects": [{"subject": "foo", "credentials": [{}]}]}`))
+		assert.EqualError(t, err, "subject #1 (foo), credential #1: type is required")
+	})
+	t.Run("credential with invalid type", func(t *testing.T) {
+		_, err := ParseJSON(strings.NewReader(`{"subjects": [{"subject": "foo", "credentials": [{"type": "%zz"}]}]}`))
+		assert.ErrorContains(t, err, "subject #1 (foo), credential #1: invalid type")
+	})
+}
@@ -0,0 +1,180 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	ssi "github.com/nuts-foundation/go-did"
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/discovery"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/issuer"
+	"github.com/nuts-foundation/nuts-admin/job"
+	"github.com/nuts-foundation/nuts-admin/model"
+)
+
+// JobType is the type of the background job that provisions the subjects of a manifest.
+const JobType = "identity-import"
+
+type Service struct {
+	IdentityService  identity.Service
+	IssuerService    issuer.Service
+	DiscoveryService discovery.Service
+	Jobs             *job.Runner
+}
+
+type importInput struct {
+	Manifest Manifest `json:"manifest"`
+	Rerun    bool     `json:"rerun"`
+}
+
+// RegisterJobHandlers registers the handlers for the background jobs of this service.
+func (s Service) RegisterJobHandlers() {
+	s.Jobs.Register(JobType, s.runImport)
+}
+
+// Import starts a background job that provisions the subjects in the manifest, reporting the result per subject.
+// If rerun is true, the import is idempotent: existing subjects, credentials already in the wallet
+// and active discovery services are skipped instead of reported as failure.
+// The calls of the job are made on behalf of the user in the context.
+func (s Service) Import(ctx context.Context, manifest Manifest, rerun bool) (*job.Job, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return s.Jobs.Start(ctx, JobType, len(manifest.Subjects), importInput{Manifest: manifest, Rerun: rerun})
+}
+
+func (s Service) runImport(ctx context.Context, data json.RawMessage, tracker job.Tracker) error {
+	var input importInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	// Subjects that failed in a previous attempt might have been partially provisioned, so a retry must be idempotent.
+	rerun := input.Rerun || tracker.IsRetry()
+	identities, err := s.IdentityService.List(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list existing subjects: %w", err)
+	}
+	existing := map[string]bool{}
+	for _, curr := range identities {
+		existing[curr.Subject] = true
+	}
+	for _, subject := range input.Manifest.Subjects {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if tracker.Succeeded(subject.Subject) {
+			tracker.Report(job.Result{Item: subject.Subject, Status: job.ResultSkipped, Message: "provisioned by previous attempt"})
+			continue
+		}
+		actions, err := s.provision(ctx, subject, existing[subject.Subject], rerun)
+		switch {
+		case err != nil:
+			tracker.Report(job.Result{Item: subject.Subject, Status: job.ResultFailed, Message: err.Error()})
+		case len(actions) == 0:
+			tracker.Report(job.Result{Item: subject.Subject, Status: job.ResultSkipped, Message: "already provisioned"})
+		default:
+			tracker.Report(job.Result{Item: subject.Subject, Status: job.ResultOK, Message: strings.Join(actions, ", ")})
+		}
+	}
+	return nil
+}
+
+// provision provisions a single subject and returns the actions that were performed.
+func (s Service) provision(ctx context.Context, manifest SubjectManifest, exists bool, rerun bool) ([]string, error) {
+	var actions []string
+	if exists && !rerun {
+		return nil, errors.New("subject already exists")
+	}
+	if !exists {
+		if _, err := s.IdentityService.Create(ctx, &manifest.Subject); err != nil {
+			return nil, fmt.Errorf("unable to create subject: %w", err)
+		}
+		actions = append(actions, "created subject")
+	}
+	details, err := s.IdentityService.Get(ctx, manifest.Subject)
+	if err != nil {
+		return actions, fmt.Errorf("unable to retrieve subject: %w", err)
+	}
+	subjectDID := preferredDID(details.DIDs)
+	if subjectDID == "" {
+		return actions, fmt.Errorf("subject has no DIDs")
+	}
+
+	for _, credential := range manifest.Credentials {
+		credentialType, err := ssi.ParseURI(credential.Type)
+		if err != nil {
+			return actions, fmt.Errorf("invalid credential type %s: %w", credential.Type, err)
+		}
+		if rerun && slices.ContainsFunc(details.WalletCredentials, func(curr model.CredentialWithStatus) bool {
+			return curr.Status == "active" && vc.VerifiableCredential(curr.VerifiableCredential).IsType(*credentialType) &&
+				slices.Contains(details.DIDs, curr.Issuer.String())
+		}) {
+			continue
+		}
+		if err := s.selfIssue(ctx, manifest.Subject, subjectDID, credential); err != nil {
+			return actions, fmt.Errorf("unable to issue %s: %w", credential.Type, err)
+		}
+		actions = append(actions, "issued "+credential.Type)
+	}
+
+	for _, serviceID := range manifest.DiscoveryServices {
+		if rerun && slices.ContainsFunc(details.DiscoveryServices, func(curr discovery.DIDStatus) bool {
+			return curr.ServiceID == serviceID && curr.Active
+		}) {
+			continue
+		}
+		if err := s.DiscoveryService.Activate(ctx, serviceID, manifest.Subject, nil); err != nil {
+			return actions, fmt.Errorf("unable to activate discovery service %s: %w", serviceID, err)
+		}
+		actions = append(actions, "activated "+serviceID)
+	}
+	return actions, nil
+}
+
+func (s Service) selfIssue(ctx context.Context, subjectID string, subjectDID string, manifest CredentialManifest) error {
+	validDays := manifest.ValidDays
+	if validDays == 0 {
+		validDays = defaultValidDays
+	}
+	credentialContext := manifest.Context
+	if credentialContext == "" {
+		credentialContext = defaultCredentialContext
+	}
+	credentialSubject := map[string]interface{}{}
+	for key, value := range manifest.CredentialSubject {
+		credentialSubject[key] = value
+	}
+	credentialSubject["id"] = subjectDID
+	expirationDate := time.Now().AddDate(0, 0, validDays)
+	credential, err := s.IssuerService.IssueCredential(ctx, issuer.IssueRequest{
+		Context:                      issuer.Values{credentialContext},
+		Type:                         issuer.Values{manifest.Type},
+		Issuer:                       subjectDID,
+		CredentialSubject:            credentialSubject,
+		ExpirationDate:               &expirationDate,
+		WithStatusList2021Revocation: true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.IdentityService.LoadCredential(ctx, subjectID, *credential)
+}
+
+// preferredDID returns the DID used to self-issue credentials: did:web if the subject has one, otherwise the first DID.
+func preferredDID(dids []string) string {
+	for _, curr := range dids {
+		if strings.HasPrefix(curr, "did:web:") {
+			return curr
+		}
+	}
+	if len(dids) > 0 {
+		return dids[0]
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package registration
+
+import (
+	"errors"
+	"time"
+)
+
+type Config struct {
+	// Interval is the time between health checks. If zero, registrations are not checked periodically.
+	Interval time.Duration `koanf:"interval"`
+	// History is the number of health checks that are kept.
+	History int `koanf:"history"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Interval: 15 * time.Minute,
+		History:  96,
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	if c.Interval < 0 {
+		errs = append(errs, errors.New("interval can't be negative"))
+	}
+	if c.History < 1 {
+		errs = append(errs, errors.New("history must be at least 1"))
+	}
+	return errors.Join(errs...)
+}
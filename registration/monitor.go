@@ -0,0 +1,270 @@
+package registration
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/discovery"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/store"
+	"github.com/rs/zerolog"
+)
+
+// Status is the health of a subject's registration on a discovery service.
+type Status string
+
+const (
+	// StatusInactive indicates the discovery service isn't activated for the subject.
+	StatusInactive Status = "inactive"
+	// StatusHealthy indicates the registration is active and present on the discovery server.
+	StatusHealthy Status = "healthy"
+	// StatusRefreshFailed indicates the Nuts node failed to register or refresh the registration.
+	StatusRefreshFailed Status = "refresh_failed"
+	// StatusExpired indicates the registered presentation expired, so it was not refreshed in time.
+	StatusExpired Status = "expired"
+	// StatusNotRegistered indicates the registration is active, but the subject is not present on the discovery server.
+	StatusNotRegistered Status = "not_registered"
+)
+
+// Registration is the health of a subject's registration on a discovery service.
+type Registration struct {
+	Subject   string `json:"subject"`
+	ServiceID string `json:"service_id"`
+	Status    Status `json:"status"`
+	// Error contains the reason the Nuts node's last attempt to register or refresh failed.
+	Error string `json:"error,omitempty"`
+	// ExpiresAt is the time the registered presentation expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// UnhealthySince is the time of the first check that found the registration unhealthy, if it's unhealthy.
+	UnhealthySince *time.Time `json:"unhealthy_since,omitempty"`
+}
+
+// Healthy returns whether the registration is healthy or inactive.
+func (r Registration) Healthy() bool {
+	return r.Status == StatusHealthy || r.Status == StatusInactive
+}
+
+func (r Registration) key() string {
+	return r.Subject + "|" + r.ServiceID
+}
+
+// Snapshot is the result of a single health check.
+type Snapshot struct {
+	CheckedAt     time.Time      `json:"checked_at"`
+	Registrations []Registration `json:"registrations"`
+}
+
+// Summary counts the registrations of a health check.
+type Summary struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Active    int       `json:"active"`
+	Unhealthy int       `json:"unhealthy"`
+}
+
+// Report contains the registrations found by the last health check, and a summary of earlier checks.
+type Report struct {
+	CheckedAt     *time.Time     `json:"checked_at,omitempty"`
+	Registrations []Registration `json:"registrations"`
+	// History summarizes the recorded health checks, oldest first.
+	History []Summary `json:"history"`
+	// Error contains the reason the last check failed, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Monitor periodically checks the registrations of all subjects on all discovery services,
+// and records the results so the health of registrations can be followed over time.
+type Monitor struct {
+	config           Config
+	identityService  identity.Service
+	discoveryService discovery.Service
+	history          *store.Collection[Snapshot]
+	logger           zerolog.Logger
+
+	mux       sync.Mutex
+	lastError string
+}
+
+func NewMonitor(config Config, identityService identity.Service, discoveryService discovery.Service, history *store.Collection[Snapshot], logger zerolog.Logger) *Monitor {
+	return &Monitor{
+		config:           config,
+		identityService:  identityService,
+		discoveryService: discoveryService,
+		history:          history,
+		logger:           logger,
+	}
+}
+
+// Start checks the registrations immediately and then periodically, until the context is cancelled.
+func (m *Monitor) Start(ctx context.Context) {
+	if m.config.Interval == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			if err := m.Check(ctx); err != nil {
+				m.logger.Error().Err(err).Msg("discovery registration health check failed")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Report returns the result of the last health check.
+func (m *Monitor) Report() Report {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	result := Report{
+		Registrations: make([]Registration, 0),
+		History:       make([]Summary, 0),
+		Error:         m.lastError,
+	}
+	snapshots := m.history.List()
+	for _, snapshot := range snapshots {
+		summary := Summary{CheckedAt: snapshot.CheckedAt}
+		for _, current := range snapshot.Registrations {
+			if current.Status != StatusInactive {
+				summary.Active++
+			}
+			if !current.Healthy() {
+				summary.Unhealthy++
+			}
+		}
+		result.History = append(result.History, summary)
+	}
+	if len(snapshots) > 0 {
+		last := snapshots[len(snapshots)-1]
+		result.CheckedAt = &last.CheckedAt
+		result.Registrations = last.Registrations
+	}
+	return result
+}
+
+// Check checks the registrations and records the result.
+func (m *Monitor) Check(ctx context.Context) error {
+	now := time.Now()
+	registrations, err := m.check(ctx, now)
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if err != nil {
+		m.lastError = err.Error()
+		return err
+	}
+	m.lastError = ""
+	return m.history.Update(func(snapshots []Snapshot) ([]Snapshot, error) {
+		previous := map[string]Registration{}
+		if len(snapshots) > 0 {
+			for _, current := range snapshots[len(snapshots)-1].Registrations {
+				previous[current.key()] = current
+			}
+		}
+		for i, current := range registrations {
+			if current.Healthy() {
+				continue
+			}
+			if before, ok := previous[current.key()]; ok && before.UnhealthySince != nil {
+				registrations[i].UnhealthySince = before.UnhealthySince
+			} else {
+				registrations[i].UnhealthySince = &now
+				m.logger.Warn().
+					Str("subject", current.Subject).
+					Str("service", current.ServiceID).
+					Str("status", string(current.Status)).
+					Str("error", current.Error).
+					Msg("discovery registration is unhealthy")
+			}
+		}
+		snapshots = append(snapshots, Snapshot{CheckedAt: now, Registrations: registrations})
+		if len(snapshots) > m.config.History {
+			snapshots = snapshots[len(snapshots)-m.config.History:]
+		}
+		return snapshots, nil
+	})
+}
+
+func (m *Monitor) check(ctx context.Context, now time.Time) ([]Registration, error) {
+	services, err := m.discoveryService.GetDiscoveryServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := m.identityService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Registration, 0)
+	for _, service := range services {
+		registered, err := m.discoveryService.Registrations(ctx, service.Id)
+		if err != nil {
+			return nil, err
+		}
+		registeredDIDs := map[string]bool{}
+		for _, current := range registered {
+			registeredDIDs[current.SubjectDID] = true
+		}
+		maxValidity := time.Duration(service.PresentationMaxValidity) * time.Second
+		for _, subject := range identities {
+			status, err := m.discoveryService.ActivationStatus(ctx, service.Id, subject.Subject)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, evaluate(subject, *status, registeredDIDs, maxValidity, now))
+		}
+	}
+	return result, nil
+}
+
+// evaluate determines the health of the subject's registration, given the DIDs registered on the discovery server.
+func evaluate(subject identity.Identity, status discovery.DIDStatus, registeredDIDs map[string]bool, maxValidity time.Duration, now time.Time) Registration {
+	result := Registration{
+		Subject:   subject.Subject,
+		ServiceID: status.ServiceID,
+		Error:     status.Error,
+	}
+	for _, presentation := range status.Presentations {
+		if expiresAt := presentationExpiry(presentation, maxValidity); expiresAt != nil && (result.ExpiresAt == nil || expiresAt.Before(*result.ExpiresAt)) {
+			result.ExpiresAt = expiresAt
+		}
+	}
+	switch {
+	case !status.Active:
+		result.Status = StatusInactive
+	case status.Error != "":
+		result.Status = StatusRefreshFailed
+	case result.ExpiresAt != nil && !result.ExpiresAt.After(now):
+		result.Status = StatusExpired
+	case !slices.ContainsFunc(subject.DIDs, func(did string) bool { return registeredDIDs[did] }):
+		result.Status = StatusNotRegistered
+	default:
+		result.Status = StatusHealthy
+	}
+	return result
+}
+
+// presentationExpiry returns the expiry of a registered presentation: the JWT expiry if set,
+// otherwise the time it was issued plus the maximum validity of presentations on the discovery service.
+func presentationExpiry(presentation vc.VerifiablePresentation, maxValidity time.Duration) *time.Time {
+	token := presentation.JWT()
+	if token == nil {
+		return nil
+	}
+	if expiresAt := token.Expiration(); !expiresAt.IsZero() {
+		return &expiresAt
+	}
+	issuedAt := token.NotBefore()
+	if issuedAt.IsZero() {
+		issuedAt = token.IssuedAt()
+	}
+	if issuedAt.IsZero() || maxValidity == 0 {
+		return nil
+	}
+	expiresAt := issuedAt.Add(maxValidity)
+	return &expiresAt
+}
@@ -0,0 +1,102 @@
+package registration
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nuts-foundation/go-did/vc"
+	"github.com/nuts-foundation/nuts-admin/discovery"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/store"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evaluate(t *testing.T) {
+	now := time.Now()
+	subject := identity.Identity{Subject: "hospital_x", DIDs: []string{"did:web:example.com:iam:1", "did:web:example.com:iam:2"}}
+	registered := map[string]bool{"did:web:example.com:iam:2": true}
+	presentation := func(t *testing.T, claims string) vc.VerifiablePresentation {
+		token := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","typ":"JWT"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"did:web:example.com:iam:2","vp":{"type":"VerifiablePresentation"},`+claims+`}`)) + ".c2lnbmF0dXJl"
+		result, err := vc.ParseVerifiablePresentation(token)
+		require.NoError(t, err)
+		return *result
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		expiresAt := now.Add(time.Hour).Truncate(time.Second)
+		status := discovery.DIDStatus{
+			ServiceID:     "service",
+			Active:        true,
+			Presentations: []vc.VerifiablePresentation{presentation(t, fmt.Sprintf(`"exp":%d`, expiresAt.Unix()))},
+		}
+
+		result := evaluate(subject, status, registered, 0, now)
+
+		assert.Equal(t, StatusHealthy, result.Status)
+		assert.Equal(t, "hospital_x", result.Subject)
+		assert.Equal(t, "service", result.ServiceID)
+		require.NotNil(t, result.ExpiresAt)
+		assert.True(t, expiresAt.Equal(*result.ExpiresAt))
+	})
+	t.Run("inactive", func(t *testing.T) {
+		result := evaluate(subject, discovery.DIDStatus{ServiceID: "service"}, nil, 0, now)
+
+		assert.Equal(t, StatusInactive, result.Status)
+		assert.True(t, result.Healthy())
+	})
+	t.Run("refresh failed", func(t *testing.T) {
+		status := discovery.DIDStatus{ServiceID: "service", Active: true, Error: "remote error"}
+
+		result := evaluate(subject, status, registered, 0, now)
+
+		assert.Equal(t, StatusRefreshFailed, result.Status)
+		assert.Equal(t, "remote error", result.Error)
+		assert.False(t, result.Healthy())
+	})
+	t.Run("expired, based on maximum validity", func(t *testing.T) {
+		status := discovery.DIDStatus{
+			ServiceID:     "service",
+			Active:        true,
+			Presentations: []vc.VerifiablePresentation{presentation(t, fmt.Sprintf(`"nbf":%d`, now.Add(-2*time.Hour).Unix()))},
+		}
+
+		result := evaluate(subject, status, registered, time.Hour, now)
+
+		assert.Equal(t, StatusExpired, result.Status)
+	})
+	t.Run("not registered", func(t *testing.T) {
+		status := discovery.DIDStatus{ServiceID: "service", Active: true}
+
+		result := evaluate(subject, status, map[string]bool{"did:web:other": true}, 0, now)
+
+		assert.Equal(t, StatusNotRegistered, result.Status)
+	})
+}
+
+func TestMonitor_Report(t *testing.T) {
+	history, err := store.Open[Snapshot]("")
+	require.NoError(t, err)
+	firstCheck := time.Now().Add(-time.Hour)
+	secondCheck := time.Now()
+	require.NoError(t, history.Add(
+		Snapshot{CheckedAt: firstCheck, Registrations: []Registration{{Subject: "a", Status: StatusHealthy}, {Subject: "b", Status: StatusInactive}}},
+		Snapshot{CheckedAt: secondCheck, Registrations: []Registration{{Subject: "a", Status: StatusNotRegistered}, {Subject: "b", Status: StatusInactive}}},
+	))
+	monitor := NewMonitor(DefaultConfig(), identity.Service{}, discovery.Service{}, history, zerolog.Nop())
+
+	report := monitor.Report()
+
+	require.NotNil(t, report.CheckedAt)
+	assert.Equal(t, secondCheck, *report.CheckedAt)
+	assert.Equal(t, []Summary{
+		{CheckedAt: firstCheck, Active: 1},
+		{CheckedAt: secondCheck, Active: 1, Unhealthy: 1},
+	}, report.History)
+	assert.Equal(t, StatusNotRegistered, report.Registrations[0].Status)
+	assert.Empty(t, report.Error)
+}
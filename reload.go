@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync/atomic"
+
+	"github.com/nuts-foundation/nuts-admin/api"
+	"github.com/nuts-foundation/nuts-admin/expiry"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/rs/zerolog"
+)
+
+// reloadableConfigKeys are the settings that are applied without restarting when the config file changes.
+var reloadableConfigKeys = []string{
+	"accesslogs",
+	"credentialprofiles",
+	"expiry.smtp.password",
+	"loglevel",
+	"proxy.maxbodysize",
+	"proxy.routes",
+}
+
+// configReloader applies changes in the config file to the running application.
+// Reloads must not run concurrently, the reload.Watcher takes care of that.
+type configReloader struct {
+	path               string
+	current            Config
+	accessLogs         *atomic.Bool
+	credentialProfiles *atomic.Pointer[[]model.CredentialProfile]
+	// proxy holds the routes, they also apply to the calls of the typed endpoints if the proxy is disabled.
+	proxy *api.Proxy
+	// expiry mails the expiry alerts, using the SMTP password from the config (which may be read from a secret file).
+	expiry *expiry.Monitor
+}
+
+// reload reads and validates the config file, then applies the reloadable settings that changed.
+// It returns the keys of the applied settings and of the changed settings that require a restart.
+// If the config is invalid, nothing is applied.
+func (r *configReloader) reload() ([]string, []string, error) {
+	config, err := readConfig(r.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	var applied, restartRequired []string
+	for _, key := range changedConfigKeys(r.current, config) {
+		if slices.Contains(reloadableConfigKeys, key) {
+			applied = append(applied, key)
+		} else {
+			restartRequired = append(restartRequired, key)
+		}
+	}
+	// The proxy goes first, since it's the only part that can fail
+	if r.proxy != nil && (slices.Contains(applied, "proxy.routes") || slices.Contains(applied, "proxy.maxbodysize")) {
+		if err := r.proxy.Reload(config.Proxy); err != nil {
+			return nil, nil, fmt.Errorf("invalid proxy config: %w", err)
+		}
+	}
+	r.current.Proxy.Routes = config.Proxy.Routes
+	r.current.Proxy.MaxBodySize = config.Proxy.MaxBodySize
+	level, _ := zerolog.ParseLevel(config.LogLevel)
+	zerolog.SetGlobalLevel(level)
+	r.current.LogLevel = config.LogLevel
+	r.accessLogs.Store(config.AccessLogs)
+	r.current.AccessLogs = config.AccessLogs
+	r.credentialProfiles.Store(&config.CredentialProfiles)
+	r.current.CredentialProfiles = config.CredentialProfiles
+	if r.expiry != nil && slices.Contains(applied, "expiry.smtp.password") {
+		r.expiry.SetSMTPPassword(config.Expiry.SMTP.Password)
+	}
+	r.current.Expiry.SMTP.Password = config.Expiry.SMTP.Password
+	return applied, restartRequired, nil
+}
+
+// changedConfigKeys returns the (dotted) koanf keys of the settings that differ between the given configs.
+// Nested config structs are compared per field, other values (including lists) as a whole.
+func changedConfigKeys(current Config, updated Config) []string {
+	return changedKeys("", reflect.ValueOf(current), reflect.ValueOf(updated))
+}
+
+func changedKeys(prefix string, current reflect.Value, updated reflect.Value) []string {
+	var result []string
+	for i := 0; i < current.NumField(); i++ {
+		field := current.Type().Field(i)
+		key := field.Tag.Get("koanf")
+		if key == "" {
+			continue
+		}
+		key = prefix + key
+		if field.Type.Kind() == reflect.Struct {
+			result = append(result, changedKeys(key+".", current.Field(i), updated.Field(i))...)
+		} else if !reflect.DeepEqual(current.Field(i).Interface(), updated.Field(i).Interface()) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
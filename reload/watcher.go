@@ -0,0 +1,116 @@
+// Package reload applies changes to the config file while the application is running.
+package reload
+
+import (
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/providers/file"
+	"github.com/rs/zerolog"
+)
+
+// Func reloads the config. It returns the changed settings that were applied,
+// and the changed settings that only take effect after a restart.
+type Func func() (applied []string, restartRequired []string, err error)
+
+// Status is the result of a reload.
+type Status struct {
+	Time    time.Time `json:"time"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+	// Applied contains the changed settings that were applied.
+	Applied []string `json:"applied"`
+	// RestartRequired contains the changed settings that only take effect after a restart.
+	RestartRequired []string `json:"restart_required"`
+}
+
+// Report describes whether the config file is watched, and the result of the last reload.
+type Report struct {
+	File     string `json:"file"`
+	Watching bool   `json:"watching"`
+	// WatchError is the reason the config file isn't watched (anymore).
+	WatchError string  `json:"watch_error,omitempty"`
+	Last       *Status `json:"last,omitempty"`
+}
+
+// Watcher reloads the config when the config file changes.
+type Watcher struct {
+	path   string
+	reload Func
+	logger zerolog.Logger
+	mux    sync.Mutex
+	report Report
+}
+
+func NewWatcher(path string, reload Func, logger zerolog.Logger) *Watcher {
+	return &Watcher{
+		path:   path,
+		reload: reload,
+		logger: logger,
+		report: Report{File: path},
+	}
+}
+
+// Start watches the config file. It returns an error if the file can't be watched, e.g. because it doesn't exist.
+func (w *Watcher) Start() error {
+	err := file.Provider(w.path).Watch(func(_ interface{}, err error) {
+		if err != nil {
+			w.logger.Warn().Err(err).Msg("Stopped watching the config file")
+			w.setWatching(false, err)
+			return
+		}
+		w.Reload()
+	})
+	w.setWatching(err == nil, err)
+	return err
+}
+
+func (w *Watcher) setWatching(watching bool, err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.report.Watching = watching
+	w.report.WatchError = ""
+	if err != nil {
+		w.report.WatchError = err.Error()
+	}
+}
+
+// Reload reloads the config and records the result.
+func (w *Watcher) Reload() Status {
+	// Reloads are serialized, so the recorded status is always the result of the last reload
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	applied, restartRequired, err := w.reload()
+	status := Status{
+		Time:            time.Now(),
+		Success:         err == nil,
+		Applied:         applied,
+		RestartRequired: restartRequired,
+	}
+	if status.Applied == nil {
+		status.Applied = make([]string, 0)
+	}
+	if status.RestartRequired == nil {
+		status.RestartRequired = make([]string, 0)
+	}
+	if err != nil {
+		status.Error = err.Error()
+		w.logger.Error().Err(err).Msg("Unable to reload the config, keeping the current config")
+	} else {
+		w.logger.Info().Msgf("Reloaded the config, applied: %v, requires restart: %v", status.Applied, status.RestartRequired)
+	}
+	w.report.Last = &status
+	return status
+}
+
+// Report returns whether the config file is watched, and the result of the last reload.
+func (w *Watcher) Report() Report {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	result := w.report
+	if result.Last != nil {
+		last := *result.Last
+		result.Last = &last
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package reload
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Run("reloads when the file changes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("accesslogs: true"), 0644))
+		var calls atomic.Int32
+		watcher := NewWatcher(path, func() ([]string, []string, error) {
+			calls.Add(1)
+			return []string{"accesslogs"}, nil, nil
+		}, zerolog.Nop())
+
+		require.NoError(t, watcher.Start())
+		require.NoError(t, os.WriteFile(path, []byte("accesslogs: false"), 0644))
+
+		assert.Eventually(t, func() bool {
+			return calls.Load() > 0
+		}, 5*time.Second, 10*time.Millisecond)
+		report := watcher.Report()
+		assert.True(t, report.Watching)
+		require.NotNil(t, report.Last)
+		assert.True(t, report.Last.Success)
+		assert.Equal(t, []string{"accesslogs"}, report.Last.Applied)
+		assert.Empty(t, report.Last.RestartRequired)
+	})
+	t.Run("failed reload", func(t *testing.T) {
+		watcher := NewWatcher("config.yaml", func() ([]string, []string, error) {
+			return nil, nil, errors.New("invalid config")
+		}, zerolog.Nop())
+
+		status := watcher.Reload()
+
+		assert.False(t, status.Success)
+		assert.Equal(t, "invalid config", status.Error)
+		assert.Equal(t, status, *watcher.Report().Last)
+	})
+	t.Run("file doesn't exist", func(t *testing.T) {
+		watcher := NewWatcher(filepath.Join(t.TempDir(), "config.yaml"), nil, zerolog.Nop())
+
+		assert.Error(t, watcher.Start())
+		report := watcher.Report()
+		assert.False(t, report.Watching)
+		assert.NotEmpty(t, report.WatchError)
+		assert.Nil(t, report.Last)
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nuts-foundation/nuts-admin/expiry"
+	"github.com/nuts-foundation/nuts-admin/identity"
+	"github.com/nuts-foundation/nuts-admin/issuer"
+	"github.com/nuts-foundation/nuts-admin/model"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedConfigKeys(t *testing.T) {
+	current := defaultConfig()
+	updated := defaultConfig()
+	updated.AccessLogs = false
+	updated.Node.Address = "http://localhost:1323"
+	updated.Proxy.Routes = updated.Proxy.Routes[1:]
+	updated.Proxy.Capture.Size = 10
+
+	keys := changedConfigKeys(current, updated)
+
+	assert.Equal(t, []string{"node.address", "accesslogs", "proxy.routes", "proxy.capture.size"}, keys)
+	assert.Empty(t, changedConfigKeys(current, defaultConfig()))
+}
+
+func TestConfigReloader_reload(t *testing.T) {
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+	setup := func(t *testing.T, contents string) *configReloader {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+		reloader := &configReloader{
+			path:               path,
+			current:            defaultConfig(),
+			accessLogs:         &atomic.Bool{},
+			credentialProfiles: &atomic.Pointer[[]model.CredentialProfile]{},
+		}
+		reloader.accessLogs.Store(true)
+		return reloader
+	}
+
+	t.Run("reloadable settings are applied", func(t *testing.T) {
+		reloader := setup(t, `
+loglevel: warn
+accesslogs: false
+port: 8080
+credentialprofiles:
+  - type: ExampleCredential
+    issuer: https://issuer.example.com
+`)
+
+		applied, restartRequired, err := reloader.reload()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"accesslogs", "loglevel", "credentialprofiles"}, applied)
+		assert.Equal(t, []string{"port"}, restartRequired)
+		assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+		assert.False(t, reloader.accessLogs.Load())
+		assert.Len(t, *reloader.credentialProfiles.Load(), 1)
+		assert.Equal(t, 1305, reloader.current.HTTPPort, "settings that require a restart aren't applied")
+	})
+	t.Run("secret file is read again", func(t *testing.T) {
+		secretFile := filepath.Join(t.TempDir(), "smtp_password")
+		require.NoError(t, os.WriteFile(secretFile, []byte("secret\n"), 0600))
+		reloader := setup(t, `
+expiry:
+  smtp:
+    password_file: `+secretFile+`
+oidc:
+  client:
+    secret: other
+`)
+		reloader.expiry = expiry.NewMonitor(reloader.current.Expiry, identity.Service{}, issuer.Service{}, zerolog.Nop())
+
+		applied, restartRequired, err := reloader.reload()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"expiry.smtp.password"}, applied)
+		assert.Equal(t, []string{"oidc.client.secret"}, restartRequired)
+		assert.Equal(t, "secret", reloader.current.Expiry.SMTP.Password)
+	})
+	t.Run("invalid config", func(t *testing.T) {
+		reloader := setup(t, `
+loglevel: loud
+accesslogs: false
+`)
+
+		_, _, err := reloader.reload()
+
+		assert.ErrorContains(t, err, "invalid loglevel")
+		assert.True(t, reloader.accessLogs.Load())
+		assert.Nil(t, reloader.credentialProfiles.Load())
+	})
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Collection is a list of records that is persisted as JSON file.
+// Every change rewrites the file, so it's meant for small amounts of administrative data.
+type Collection[T any] struct {
+	path  string
+	mux   sync.Mutex
+	items []T
+}
+
+// Open loads the collection from the given file, which is created on the first change if it doesn't exist.
+// If path is empty, the collection is only kept in memory.
+func Open[T any](path string) (*Collection[T], error) {
+	result := &Collection[T]{
+		path:  path,
+		items: make([]T, 0),
+	}
+	if path == "" {
+		return result, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &result.items); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// List returns a copy of the items in the collection.
+func (c *Collection[T]) List() []T {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return slices.Clone(c.items)
+}
+
+// Add appends the items to the collection.
+func (c *Collection[T]) Add(items ...T) error {
+	return c.Update(func(current []T) ([]T, error) {
+		return append(current, items...), nil
+	})
+}
+
+// Update replaces the items in the collection with the result of fn, which is given a copy of the current items.
+// If fn returns an error, the collection is not changed.
+func (c *Collection[T]) Update(fn func(items []T) ([]T, error)) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	updated, err := fn(slices.Clone(c.items))
+	if err != nil {
+		return err
+	}
+	if updated == nil {
+		updated = make([]T, 0)
+	}
+	if err := c.persist(updated); err != nil {
+		return err
+	}
+	c.items = updated
+	return nil
+}
+
+func (c *Collection[T]) persist(items []T) error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("unable to create directory for %s: %w", c.path, err)
+	}
+	if err := os.WriteFile(c.path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("unable to write %s: %w", c.path, err)
+	}
+	return os.Rename(c.path+".tmp", c.path)
+}
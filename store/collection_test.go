@@ -0,0 +1,57 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollection(t *testing.T) {
+	type record struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("persisted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sub", "records.json")
+		collection, err := Open[record](path)
+		require.NoError(t, err)
+
+		require.NoError(t, collection.Add(record{Name: "a"}, record{Name: "b"}))
+		reopened, err := Open[record](path)
+
+		require.NoError(t, err)
+		assert.Equal(t, []record{{Name: "a"}, {Name: "b"}}, reopened.List())
+	})
+	t.Run("in memory", func(t *testing.T) {
+		collection, err := Open[record]("")
+		require.NoError(t, err)
+		assert.Empty(t, collection.List())
+
+		require.NoError(t, collection.Add(record{Name: "a"}))
+
+		assert.Equal(t, []record{{Name: "a"}}, collection.List())
+	})
+	t.Run("update fails", func(t *testing.T) {
+		collection, _ := Open[record]("")
+		_ = collection.Add(record{Name: "a"})
+
+		err := collection.Update(func(items []record) ([]record, error) {
+			return nil, errors.New("failed")
+		})
+
+		assert.EqualError(t, err, "failed")
+		assert.Equal(t, []record{{Name: "a"}}, collection.List())
+	})
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "records.json")
+		require.NoError(t, os.WriteFile(path, []byte("not JSON"), 0600))
+
+		_, err := Open[record](path)
+
+		assert.ErrorContains(t, err, "unable to parse")
+	})
+}
@@ -0,0 +1,24 @@
+// Package validation contains helpers for reporting all problems found while validating configuration at once.
+package validation
+
+import "fmt"
+
+// Errors returns the errors joined in err (see errors.Join), err itself if it doesn't join errors, or nil if err is nil.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// Prefix returns the errors joined in err (see Errors), each prefixed to tell where it occurred, e.g. "profile 1: ...".
+func Prefix(prefix string, err error) []error {
+	var result []error
+	for _, current := range Errors(err) {
+		result = append(result, fmt.Errorf("%s: %w", prefix, current))
+	}
+	return result
+}
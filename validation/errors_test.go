@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefix(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+
+	t.Run("joined", func(t *testing.T) {
+		result := Prefix("profile 1", errors.Join(first, second))
+
+		assert.Len(t, result, 2)
+		assert.EqualError(t, result[0], "profile 1: first")
+		assert.ErrorIs(t, result[1], second)
+	})
+	t.Run("single", func(t *testing.T) {
+		result := Prefix("profile 1", first)
+
+		assert.Len(t, result, 1)
+		assert.EqualError(t, result[0], "profile 1: first")
+	})
+	t.Run("nil", func(t *testing.T) {
+		assert.Empty(t, Prefix("profile 1", nil))
+	})
+}
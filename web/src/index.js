@@ -16,6 +16,7 @@ import IdentityDetails from "./admin/IdentityDetails.vue";
 import IssueCredential from "./admin/credentials/IssueCredential.vue";
 import ActivateDiscoveryService from "./admin/ActivateDiscoveryService.vue";
 import UploadCredential from "./admin/credentials/UploadCredential.vue";
+import VerifyTool from "./admin/VerifyTool.vue";
 
 const routes = [
   {
@@ -82,6 +83,11 @@ const routes = [
         path: 'discovery',
         name: 'admin.discovery',
         component: DiscoveryServices
+      },
+      {
+        path: 'tools/verify',
+        name: 'admin.verify',
+        component: VerifyTool
       }
     ],
   },
@@ -74,6 +74,36 @@ export default {
       }
     })
 
+    // download fetches a binary response (e.g. a ZIP file) as Blob
+    api.download = (url) => {
+      return fetch(url, {...defaultOptions, headers: authHeader()})
+        .then((response) => {
+          if (response.ok) {
+            return response.blob()
+          }
+          return response.text().then((text) => Promise.reject(text || response.statusText))
+        })
+    }
+
+    // upload posts a file as request body, using the file's media type as Content-Type
+    api.upload = (url, file) => {
+      const options = {
+        ...defaultOptions,
+        method: 'POST',
+        headers: {
+          'Content-Type': file.type || 'text/plain',
+          ...authHeader()
+        },
+        body: file
+      }
+      return fetch(url, options)
+        .then((response) => {
+          return response.json()
+            .catch(() => Promise.reject(response.statusText))
+            .then((data) => response.ok ? data : Promise.reject(data.message || data.error || response.statusText))
+        })
+    }
+
     app.config.globalProperties.$api = api
   }
 }